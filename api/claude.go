@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 	"yact/logic"
@@ -17,48 +18,136 @@ type ClaudeClient struct {
 	apiKey          string
 	model           string
 	maxOutputTokens int
+	maxRetries      int
 }
 
 func (c *ClaudeClient) Init(cfg *config.Config) {
 	c.apiKey = cfg.AnthropicAPIKey
 	c.model = cfg.ClaudeModel
 	c.maxOutputTokens = cfg.MaxOutputTokens
+	c.maxRetries = cfg.MaxRetries
 }
 
 func (c *ClaudeClient) GetModelName() string {
 	return c.model
 }
 
-func (c *ClaudeClient) calculateCost(inputTokens int64, outputTokens int64) float64 {
-	var inputCostPer1M, outputCostPer1M float64
+type claudeUsage struct {
+	inputTokens      int64
+	outputTokens     int64
+	cacheWriteTokens int64
+	cacheReadTokens  int64
+}
+
+const (
+	cacheWriteMultiplier = 1.25
+	cacheReadMultiplier  = 0.1
+)
 
+func (c *ClaudeClient) prices() (float64, float64) {
 	switch {
 	case strings.Contains(c.model, "haiku"):
-		inputCostPer1M = 0.80
-		outputCostPer1M = 4.0
+		return 0.80, 4.0
 	case strings.Contains(c.model, "sonnet"):
-		inputCostPer1M = 3.0
-		outputCostPer1M = 15.0
+		return 3.0, 15.0
 	case strings.Contains(c.model, "opus"):
-		inputCostPer1M = 15.0
-		outputCostPer1M = 75.0
+		return 15.0, 75.0
 	default:
-		return 0.0
+		return 0.0, 0.0
+	}
+}
+
+func (c *ClaudeClient) calculateCost(usage claudeUsage) (float64, float64) {
+	inputCostPer1M, outputCostPer1M := c.prices()
+
+	inputCost := (float64(usage.inputTokens) / 1_000_000) * inputCostPer1M
+	cacheWriteCost := (float64(usage.cacheWriteTokens) / 1_000_000) * inputCostPer1M * cacheWriteMultiplier
+	cacheReadCost := (float64(usage.cacheReadTokens) / 1_000_000) * inputCostPer1M * cacheReadMultiplier
+	outputCost := (float64(usage.outputTokens) / 1_000_000) * outputCostPer1M
+
+	uncachedCost := (float64(usage.inputTokens+usage.cacheWriteTokens+usage.cacheReadTokens) / 1_000_000) * inputCostPer1M
+	savings := uncachedCost - (inputCost + cacheWriteCost + cacheReadCost)
+
+	return inputCost + cacheWriteCost + cacheReadCost + outputCost, savings
+}
+
+func usageFromMessage(message anthropic.Message) claudeUsage {
+	extraTokens := func(name string) int64 {
+		field, ok := message.Usage.JSON.ExtraFields[name]
+		if !ok {
+			return 0
+		}
+		value, err := strconv.ParseInt(field.Raw(), 10, 64)
+		if err != nil {
+			return 0
+		}
+		return value
+	}
+
+	return claudeUsage{
+		inputTokens:      message.Usage.InputTokens,
+		outputTokens:     message.Usage.OutputTokens,
+		cacheWriteTokens: extraTokens("cache_creation_input_tokens"),
+		cacheReadTokens:  extraTokens("cache_read_input_tokens"),
+	}
+}
+
+func cacheBreakpoints(messages []logic.Message, systemPrompt string) []option.RequestOption {
+	ephemeral := map[string]string{"type": "ephemeral"}
+
+	var options []option.RequestOption
+	if systemPrompt != "" {
+		options = append(options, option.WithJSONSet("system.0.cache_control", ephemeral))
+	}
+
+	prefixEnd := -1
+	for i, msg := range messages {
+		if msg.Type != logic.MessageTypeFile {
+			break
+		}
+		prefixEnd = i
+	}
+
+	lastFile := -1
+	for i, msg := range messages {
+		if msg.Type == logic.MessageTypeFile {
+			lastFile = i
+		}
+	}
+
+	breakpoints := []int{prefixEnd}
+	if lastFile != prefixEnd {
+		breakpoints = append(breakpoints, lastFile)
+	}
+
+	for _, idx := range breakpoints {
+		if idx < 0 {
+			continue
+		}
+		options = append(options, option.WithJSONSet(fmt.Sprintf("messages.%d.content.0.cache_control", idx), ephemeral))
 	}
 
-	inputCost := (float64(inputTokens) / 1_000_000) * inputCostPer1M
-	outputCost := (float64(outputTokens) / 1_000_000) * outputCostPer1M
-	return inputCost + outputCost
+	return options
 }
 
-func (c *ClaudeClient) Call(messages []logic.Message, systemPrompt string) (logic.Message, error) {
+func (c *ClaudeClient) Call(ctx context.Context, messages []logic.Message, systemPrompt string) (logic.Message, error) {
+	return c.Stream(ctx, messages, systemPrompt, nil)
+}
+
+func (c *ClaudeClient) Stream(ctx context.Context, messages []logic.Message, systemPrompt string, onDelta func(string)) (logic.Message, error) {
 	if c.apiKey == "" {
 		return logic.Message{}, fmt.Errorf("Claude API key not configured. Please set your API key with: y config anthropic_api_key YOUR_API_KEY")
 	}
 
+	return withRetry(ctx, c.maxRetries, onDelta, func(onDelta func(string)) (logic.Message, error) {
+		return c.streamOnce(ctx, messages, systemPrompt, onDelta)
+	})
+}
+
+func (c *ClaudeClient) streamOnce(ctx context.Context, messages []logic.Message, systemPrompt string, onDelta func(string)) (logic.Message, error) {
 	startTime := time.Now()
 
-	client := anthropic.NewClient(option.WithAPIKey(c.apiKey))
+	client := anthropic.NewClient(option.WithAPIKey(c.apiKey), option.WithMaxRetries(0))
 
 	messageParams := make([]anthropic.MessageParam, len(messages))
 	for i, msg := range messages {
@@ -83,20 +172,40 @@ func (c *ClaudeClient) Call(messages []logic.Message, systemPrompt string) (logi
 
 	fmt.Printf("Calling Claude with %d messages\n", len(messages))
 
-	message, err := client.Messages.New(context.Background(), params)
+	stream := client.Messages.NewStreaming(ctx, params, cacheBreakpoints(messages, systemPrompt)...)
+	defer stream.Close()
 
-	if err != nil {
-		return logic.Message{}, fmt.Errorf("error calling Claude API: %w", err)
+	message := anthropic.Message{}
+	for stream.Next() {
+		event := stream.Current()
+		if err := message.Accumulate(event); err != nil {
+			return logic.Message{}, fmt.Errorf("error reading Claude API stream: %w", classifyClaudeError(err))
+		}
+
+		if delta, ok := event.Delta.(anthropic.ContentBlockDeltaEventDelta); ok && delta.Text != "" && onDelta != nil {
+			onDelta(delta.Text)
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		return logic.Message{}, fmt.Errorf("error calling Claude API: %w", classifyClaudeError(err))
+	}
+
+	if onDelta != nil {
+		fmt.Println()
 	}
 
 	duration := time.Since(startTime)
 	fmt.Printf("Claude API call took %.2f seconds\n", duration.Seconds())
-	fmt.Printf("Token usage - Input: %d, Output: %d\n",
-		message.Usage.InputTokens,
-		message.Usage.OutputTokens)
+	usage := usageFromMessage(message)
+	fmt.Printf("Token usage - Input: %d, Cache write: %d, Cache read: %d, Output: %d\n",
+		usage.inputTokens,
+		usage.cacheWriteTokens,
+		usage.cacheReadTokens,
+		usage.outputTokens)
 
-	cost := c.calculateCost(message.Usage.InputTokens, message.Usage.OutputTokens)
-	fmt.Printf("Cost: $%.6f\n", cost)
+	cost, savings := c.calculateCost(usage)
+	fmt.Printf("Cost: $%.6f (cache saved: $%.6f)\n", cost, savings)
 
 	if message.Usage.OutputTokens >= int64(c.maxOutputTokens) {
 		fmt.Printf("⚠️  WARNING: Maximum output tokens (%d) reached. Response may be incomplete.\n", c.maxOutputTokens)
@@ -111,3 +220,47 @@ func (c *ClaudeClient) Call(messages []logic.Message, systemPrompt string) (logi
 		Content: responseText,
 	}, nil
 }
+
+func (c *ClaudeClient) CountTokens(ctx context.Context, messages []logic.Message, systemPrompt string) (int64, error) {
+	if c.apiKey == "" {
+		return 0, fmt.Errorf("Claude API key not configured")
+	}
+
+	client := anthropic.NewClient(option.WithAPIKey(c.apiKey), option.WithMaxRetries(0))
+
+	messageParams := make([]anthropic.BetaMessageParam, len(messages))
+	for i, msg := range messages {
+		role := anthropic.BetaMessageParamRoleUser
+		if msg.Type == logic.MessageTypeAction {
+			role = anthropic.BetaMessageParamRoleAssistant
+		}
+		messageParams[i] = anthropic.BetaMessageParam{
+			Role: anthropic.F(role),
+			Content: anthropic.F([]anthropic.BetaContentBlockParamUnion{anthropic.BetaTextBlockParam{
+				Type: anthropic.F(anthropic.BetaTextBlockParamTypeText),
+				Text: anthropic.F(msg.Content),
+			}}),
+		}
+	}
+
+	params := anthropic.BetaMessageCountTokensParams{
+		Model:    anthropic.F(c.model),
+		Messages: anthropic.F(messageParams),
+		Betas:    anthropic.F([]anthropic.AnthropicBeta{anthropic.AnthropicBetaTokenCounting2024_11_01}),
+	}
+
+	if systemPrompt != "" {
+		params.System = anthropic.F[anthropic.BetaMessageCountTokensParamsSystemUnion](anthropic.BetaMessageCountTokensParamsSystemArray{
+			anthropic.BetaTextBlockParam{
+				Type: anthropic.F(anthropic.BetaTextBlockParamTypeText),
+				Text: anthropic.F(systemPrompt),
+			},
+		})
+	}
+
+	count, err := client.Beta.Messages.CountTokens(ctx, params)
+	if err != nil {
+		return 0, classifyClaudeError(err)
+	}
+	return count.InputTokens, nil
+}
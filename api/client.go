@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"yact/config"
 	"yact/logic"
 )
@@ -8,5 +10,26 @@ import (
 type Client interface {
 	Init(cfg *config.Config)
 	GetModelName() string
-	Call(messages []logic.Message, systemPrompt string) (logic.Message, error)
+	Call(ctx context.Context, messages []logic.Message, systemPrompt string) (logic.Message, error)
+	Stream(ctx context.Context, messages []logic.Message, systemPrompt string, onDelta func(string)) (logic.Message, error)
+}
+
+type TokenCounter interface {
+	CountTokens(ctx context.Context, messages []logic.Message, systemPrompt string) (int64, error)
+}
+
+func NewClient(cfg *config.Config) (Client, error) {
+	var client Client
+
+	switch cfg.Provider {
+	case "", config.ProviderAnthropic:
+		client = &ClaudeClient{}
+	case config.ProviderOpenAI:
+		client = &OpenAIClient{}
+	default:
+		return nil, fmt.Errorf("unknown provider '%s'", cfg.Provider)
+	}
+
+	client.Init(cfg)
+	return client, nil
 }
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+type ErrorKind string
+
+const (
+	ErrorKindAuth       ErrorKind = "authentication"
+	ErrorKindRateLimit  ErrorKind = "rate limit"
+	ErrorKindOverloaded ErrorKind = "overloaded"
+	ErrorKindBadRequest ErrorKind = "bad request"
+	ErrorKindServer     ErrorKind = "server"
+	ErrorKindNetwork    ErrorKind = "network"
+	ErrorKindUnknown    ErrorKind = "unknown"
+)
+
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s error: %v", e.Kind, e.Err)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Retryable() bool {
+	switch e.Kind {
+	case ErrorKindRateLimit, ErrorKindOverloaded, ErrorKindServer, ErrorKindNetwork:
+		return true
+	default:
+		return false
+	}
+}
+
+func kindForStatus(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorKindAuth
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimit
+	case statusCode == 529:
+		return ErrorKindOverloaded
+	case statusCode >= 500:
+		return ErrorKindServer
+	case statusCode >= 400:
+		return ErrorKindBadRequest
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+func parseRetryAfter(header http.Header) time.Duration {
+	if header == nil {
+		return 0
+	}
+
+	if ms := header.Get("retry-after-ms"); ms != "" {
+		if value, err := strconv.ParseFloat(ms, 64); err == nil && value > 0 {
+			return time.Duration(value * float64(time.Millisecond))
+		}
+	}
+
+	value := header.Get("retry-after")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+func newHTTPError(statusCode int, header http.Header, err error) *APIError {
+	return &APIError{
+		Kind:       kindForStatus(statusCode),
+		StatusCode: statusCode,
+		RetryAfter: parseRetryAfter(header),
+		Err:        err,
+	}
+}
+
+func classifyClaudeError(err error) *APIError {
+	var apiErr *anthropic.Error
+	if errors.As(err, &apiErr) {
+		var header http.Header
+		if apiErr.Response != nil {
+			header = apiErr.Response.Header
+		}
+		return newHTTPError(apiErr.StatusCode, header, err)
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "overloaded_error"):
+		return &APIError{Kind: ErrorKindOverloaded, Err: err}
+	case strings.Contains(message, "rate_limit_error"):
+		return &APIError{Kind: ErrorKindRateLimit, Err: err}
+	case strings.Contains(message, "api_error"):
+		return &APIError{Kind: ErrorKindServer, Err: err}
+	case strings.Contains(message, "received error while streaming"):
+		return &APIError{Kind: ErrorKindUnknown, Err: err}
+	default:
+		return &APIError{Kind: ErrorKindNetwork, Err: err}
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"yact/logic"
+
+	"yact/config"
+)
+
+type OpenAIClient struct {
+	apiKey          string
+	baseURL         string
+	model           string
+	maxOutputTokens int
+	maxRetries      int
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIRequest struct {
+	Model         string              `json:"model"`
+	Messages      []openAIMessage     `json:"messages"`
+	MaxTokens     int                 `json:"max_tokens"`
+	Stream        bool                `json:"stream"`
+	StreamOptions openAIStreamOptions `json:"stream_options"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+type openAIChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+func (c *OpenAIClient) Init(cfg *config.Config) {
+	c.apiKey = cfg.OpenAIAPIKey
+	c.baseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if c.baseURL == "" {
+		c.baseURL = config.OpenAIBaseURL
+	}
+	c.model = cfg.OpenAIModel
+	c.maxOutputTokens = cfg.MaxOutputTokens
+	c.maxRetries = cfg.MaxRetries
+}
+
+func (c *OpenAIClient) GetModelName() string {
+	return c.model
+}
+
+func (c *OpenAIClient) Call(ctx context.Context, messages []logic.Message, systemPrompt string) (logic.Message, error) {
+	return c.Stream(ctx, messages, systemPrompt, nil)
+}
+
+func (c *OpenAIClient) Stream(ctx context.Context, messages []logic.Message, systemPrompt string, onDelta func(string)) (logic.Message, error) {
+	if c.model == "" {
+		return logic.Message{}, fmt.Errorf("OpenAI model not configured. Please set it with: y config openai_model MODEL_NAME")
+	}
+
+	return withRetry(ctx, c.maxRetries, onDelta, func(onDelta func(string)) (logic.Message, error) {
+		return c.streamOnce(ctx, messages, systemPrompt, onDelta)
+	})
+}
+
+func (c *OpenAIClient) streamOnce(ctx context.Context, messages []logic.Message, systemPrompt string, onDelta func(string)) (logic.Message, error) {
+	startTime := time.Now()
+
+	var chatMessages []openAIMessage
+	if systemPrompt != "" {
+		chatMessages = append(chatMessages, openAIMessage{Role: "system", Content: systemPrompt})
+	}
+	for _, msg := range messages {
+		if msg.Type == logic.MessageTypeAction {
+			chatMessages = append(chatMessages, openAIMessage{Role: "assistant", Content: msg.Content})
+		} else {
+			chatMessages = append(chatMessages, openAIMessage{Role: "user", Content: msg.Content})
+		}
+	}
+
+	body, err := json.Marshal(openAIRequest{
+		Model:         c.model,
+		Messages:      chatMessages,
+		MaxTokens:     c.maxOutputTokens,
+		Stream:        true,
+		StreamOptions: openAIStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return logic.Message{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return logic.Message{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	fmt.Printf("Calling %s with %d messages\n", c.baseURL, len(messages))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return logic.Message{}, fmt.Errorf("error calling OpenAI-compatible API: %w", &APIError{Kind: ErrorKindNetwork, Err: err})
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		statusErr := fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+		return logic.Message{}, fmt.Errorf("error calling OpenAI-compatible API: %w", newHTTPError(resp.StatusCode, resp.Header, statusErr))
+	}
+
+	responseText, usage, finishReason, err := readOpenAIStream(resp.Body, onDelta)
+	if err != nil {
+		return logic.Message{}, err
+	}
+
+	if onDelta != nil {
+		fmt.Println()
+	}
+
+	duration := time.Since(startTime)
+	fmt.Printf("API call took %.2f seconds\n", duration.Seconds())
+	fmt.Printf("Token usage - Input: %d, Output: %d\n", usage.PromptTokens, usage.CompletionTokens)
+
+	if finishReason == "length" {
+		fmt.Printf("⚠️  WARNING: Maximum output tokens (%d) reached. Response may be incomplete.\n", c.maxOutputTokens)
+	}
+
+	return logic.Message{
+		Content: responseText,
+	}, nil
+}
+
+func readOpenAIStream(body io.Reader, onDelta func(string)) (string, openAIUsage, string, error) {
+	var responseText strings.Builder
+	var usage openAIUsage
+	finishReason := ""
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", usage, "", fmt.Errorf("error reading OpenAI-compatible API stream: %w", err)
+		}
+
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != "" {
+				finishReason = choice.FinishReason
+			}
+			if choice.Delta.Content == "" {
+				continue
+			}
+			responseText.WriteString(choice.Delta.Content)
+			if onDelta != nil {
+				onDelta(choice.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", usage, "", fmt.Errorf("error reading OpenAI-compatible API stream: %w", &APIError{Kind: ErrorKindNetwork, Err: err})
+	}
+
+	return responseText.String(), usage, finishReason, nil
+}
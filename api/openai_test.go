@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"yact/config"
+	"yact/logic"
+)
+
+func newTestOpenAIClient(url string) *OpenAIClient {
+	client := &OpenAIClient{}
+	client.Init(&config.Config{
+		OpenAIAPIKey:    "test-key",
+		OpenAIModel:     "test-model",
+		BaseURL:         url + "/",
+		MaxOutputTokens: 100,
+	})
+	return client
+}
+
+func writeSSE(w http.ResponseWriter, chunks ...string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, chunk := range chunks {
+		fmt.Fprintf(w, "data: %s\n\n", chunk)
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func TestOpenAIClientStream(t *testing.T) {
+	var request openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("path = %q, want /chat/completions", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decoding request: %v", err)
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		writeSSE(w,
+			`{"choices":[{"delta":{"role":"assistant"}}]}`,
+			`{"choices":[{"delta":{"content":"Hello"}}]}`,
+			`{"choices":[{"delta":{"content":", world"},"finish_reason":"stop"}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":3}}`,
+		)
+	}))
+	defer server.Close()
+
+	messages := []logic.Message{
+		{Type: logic.MessageTypeFile, Path: "a.go", Content: "file"},
+		{Type: logic.MessageTypeCommand, Content: "do it"},
+		{Type: logic.MessageTypeAction, Content: "done"},
+		{Type: logic.MessageTypeQuestion, Content: "why?"},
+	}
+
+	var deltas []string
+	response, err := newTestOpenAIClient(server.URL).Stream(context.Background(), messages, "system prompt", func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	if response.Content != "Hello, world" {
+		t.Errorf("content = %q, want %q", response.Content, "Hello, world")
+	}
+	if strings.Join(deltas, "|") != "Hello|, world" {
+		t.Errorf("deltas = %q", deltas)
+	}
+
+	if request.Model != "test-model" || !request.Stream || request.MaxTokens != 100 || !request.StreamOptions.IncludeUsage {
+		t.Errorf("unexpected request parameters: %+v", request)
+	}
+
+	wantRoles := []string{"system", "user", "user", "assistant", "user"}
+	if len(request.Messages) != len(wantRoles) {
+		t.Fatalf("got %d messages, want %d", len(request.Messages), len(wantRoles))
+	}
+	for i, role := range wantRoles {
+		if request.Messages[i].Role != role {
+			t.Errorf("message %d role = %q, want %q", i, request.Messages[i].Role, role)
+		}
+	}
+	if request.Messages[0].Content != "system prompt" {
+		t.Errorf("system message = %q", request.Messages[0].Content)
+	}
+}
+
+func TestReadOpenAIStreamUsage(t *testing.T) {
+	stream := strings.Join([]string{
+		`data: {"choices":[{"delta":{"content":"partial"},"finish_reason":"length"}]}`,
+		``,
+		`: keep-alive comment`,
+		`data: {"choices":[],"usage":{"prompt_tokens":42,"completion_tokens":7}}`,
+		``,
+		`data: [DONE]`,
+		`data: {"choices":[{"delta":{"content":"after done"}}]}`,
+	}, "\n")
+
+	text, usage, finishReason, err := readOpenAIStream(strings.NewReader(stream), nil)
+	if err != nil {
+		t.Fatalf("readOpenAIStream: %v", err)
+	}
+	if text != "partial" {
+		t.Errorf("text = %q, want %q", text, "partial")
+	}
+	if usage.PromptTokens != 42 || usage.CompletionTokens != 7 {
+		t.Errorf("usage = %+v", usage)
+	}
+	if finishReason != "length" {
+		t.Errorf("finish reason = %q, want length", finishReason)
+	}
+}
+
+func TestOpenAIClientErrorResponse(t *testing.T) {
+	tests := []struct {
+		status    int
+		kind      ErrorKind
+		retryable bool
+	}{
+		{http.StatusBadRequest, ErrorKindBadRequest, false},
+		{http.StatusUnauthorized, ErrorKindAuth, false},
+		{http.StatusTooManyRequests, ErrorKindRateLimit, true},
+		{http.StatusInternalServerError, ErrorKindServer, true},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				http.Error(w, `{"error":{"message":"nope"}}`, test.status)
+			}))
+			defer server.Close()
+
+			_, err := newTestOpenAIClient(server.URL).Call(context.Background(), []logic.Message{{Type: logic.MessageTypeQuestion, Content: "q"}}, "")
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want an APIError", err)
+			}
+			if apiErr.StatusCode != test.status || apiErr.Kind != test.kind || apiErr.Retryable() != test.retryable {
+				t.Errorf("got status %d kind %s retryable %v", apiErr.StatusCode, apiErr.Kind, apiErr.Retryable())
+			}
+			if !strings.Contains(err.Error(), "nope") {
+				t.Errorf("error %q does not include the response body", err)
+			}
+			if calls != 1 {
+				t.Errorf("server called %d times, want 1 with retries disabled", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+	"yact/logic"
+)
+
+const (
+	retryBaseDelay = 1 * time.Second
+	retryMaxDelay  = 60 * time.Second
+)
+
+func backoffDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := retryBaseDelay << attempt
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func withRetry(ctx context.Context, maxRetries int, onDelta func(string), call func(onDelta func(string)) (logic.Message, error)) (logic.Message, error) {
+	emitted := false
+	trackedDelta := func(delta string) {
+		emitted = true
+		onDelta(delta)
+	}
+	if onDelta == nil {
+		trackedDelta = nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		message, err := call(trackedDelta)
+		if err == nil {
+			return message, nil
+		}
+
+		if ctx.Err() != nil {
+			return logic.Message{}, ctx.Err()
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt >= maxRetries || emitted {
+			return logic.Message{}, err
+		}
+
+		delay := backoffDelay(attempt, apiErr.RetryAfter)
+		fmt.Printf("\r%s error, retrying in %.1f seconds (attempt %d of %d)\n", apiErr.Kind, delay.Seconds(), attempt+1, maxRetries)
+		select {
+		case <-ctx.Done():
+			return logic.Message{}, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"yact/logic"
+)
+
+func lastPlanIndex(messages []logic.Message) int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Type == logic.MessageTypePlan {
+			return i
+		}
+	}
+	return -1
+}
+
+func HandleAcceptCommand(edit bool) error {
+	messages, err := logic.LoadContext()
+	if err != nil {
+		return fmt.Errorf("error loading context: %w", err)
+	}
+
+	idx := lastPlanIndex(messages)
+	if idx == -1 {
+		return fmt.Errorf("no plan to accept; create one with 'y plan'")
+	}
+	original := messages[idx].Content
+
+	content := original
+	if edit {
+		content, err = editInEditor(logic.CodeBlock{Path: "plan.md", Content: original})
+		if err != nil {
+			return fmt.Errorf("error editing plan: %w", err)
+		}
+		if strings.TrimSpace(content) == "" {
+			return fmt.Errorf("plan is empty, not accepted")
+		}
+	}
+
+	err = logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		if lastPlanIndex(messages) != idx || messages[idx].Content != original {
+			return nil, fmt.Errorf("context changed while accepting the plan, try again")
+		}
+
+		steps := messages[idx].Steps
+		if content != original {
+			steps = logic.ParsePlanSteps(content)
+		}
+		messages[idx] = logic.Message{Type: logic.MessageTypeCommand, Content: content, Steps: steps}
+		return messages, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Accepted plan at index %d as instruction\n", idx)
+	return nil
+}
@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"yact/logic"
+)
+
+func HandleCheckpointCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("checkpoint name required")
+	}
+
+	if err := logic.CreateCheckpoint(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created checkpoint %s\n", args[0])
+	return nil
+}
+
+func HandleBranchCommand(args []string) error {
+	if len(args) == 0 {
+		return listConversationTree()
+	}
+
+	if len(args) > 2 {
+		return fmt.Errorf("usage: y branch [<name> [<from>]]")
+	}
+
+	from := ""
+	if len(args) == 2 {
+		from = args[1]
+	}
+
+	if err := logic.CreateBranch(args[0], from); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created and switched to branch %s\n", args[0])
+	return nil
+}
+
+func HandleCheckoutCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("branch or checkpoint name required")
+	}
+
+	branch, err := logic.Checkout(args[0])
+	if err != nil {
+		return err
+	}
+
+	if branch != args[0] {
+		fmt.Printf("Created branch %s from checkpoint %s\n", branch, args[0])
+	}
+	fmt.Printf("Switched to branch %s\n", branch)
+	return nil
+}
+
+func listConversationTree() error {
+	tree, err := logic.LoadConversationTree()
+	if err != nil {
+		return err
+	}
+
+	var roots []logic.ConversationNode
+	for _, node := range tree.Nodes {
+		if node.Parent == "" || tree.Find(node.Parent) == nil {
+			roots = append(roots, node)
+		}
+	}
+
+	for _, root := range roots {
+		printConversationNode(tree, root, 0)
+	}
+	return nil
+}
+
+func printConversationNode(tree *logic.ConversationTree, node logic.ConversationNode, depth int) {
+	marker := " "
+	if node.Name == tree.Current {
+		marker = "*"
+	}
+
+	fmt.Printf("%s %s%s (%s, %d messages, %s)\n",
+		marker,
+		strings.Repeat("  ", depth),
+		node.Name,
+		node.Kind,
+		len(node.Messages),
+		node.Created.Format("2006-01-02 15:04"))
+
+	for _, child := range tree.Children(node.Name) {
+		printConversationNode(tree, child, depth+1)
+	}
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"yact/api"
+	"yact/config"
+	"yact/logic"
+)
+
+func countInputTokens(ctx context.Context, client api.Client, messages []logic.Message, systemPrompt string) (int64, string) {
+	if counter, ok := client.(api.TokenCounter); ok {
+		count, err := counter.CountTokens(ctx, messages, systemPrompt)
+		if err == nil {
+			return count, "counted"
+		}
+		fmt.Printf("Warning: could not count tokens, using an estimate: %v\n", err)
+	}
+	return int64(logic.EstimateMessagesTokens(messages, systemPrompt)), "estimated"
+}
+
+func checkTokenBudget(ctx context.Context, client api.Client, messages []logic.Message, systemPrompt string, cfg *config.Config) error {
+	if cfg.TokenBudget <= 0 {
+		return nil
+	}
+
+	tokens, method := countInputTokens(ctx, client, messages, systemPrompt)
+	fmt.Printf("Input tokens (%s): %d of %d budget\n", method, tokens, cfg.TokenBudget)
+
+	if tokens <= int64(cfg.TokenBudget) {
+		return nil
+	}
+
+	if cfg.TokenBudgetAction == config.BudgetActionRefuse {
+		return fmt.Errorf("request has %d input tokens, over the budget of %d; drop messages with 'y del' or 'y pop', or run 'y compact'", tokens, cfg.TokenBudget)
+	}
+
+	fmt.Printf("⚠️  WARNING: request has %d input tokens, over the budget of %d\n", tokens, cfg.TokenBudget)
+	return nil
+}
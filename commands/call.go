@@ -1,8 +1,10 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"yact/api"
@@ -10,42 +12,89 @@ import (
 	"yact/logic"
 )
 
-func showProgress(done chan bool) {
+type spinner struct {
+	done chan bool
+	once sync.Once
+	mu   sync.Mutex
+}
+
+func startSpinner() *spinner {
+	s := &spinner{done: make(chan bool)}
+	go s.showProgress()
+	return s
+}
+
+func (s *spinner) showProgress() {
 	chars := []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
 	idx := 0
 	for {
 		select {
-		case <-done:
+		case <-s.done:
+			s.mu.Lock()
 			fmt.Print("\r \r")
+			s.mu.Unlock()
 			return
 		default:
+			s.mu.Lock()
 			fmt.Printf("\r%c", chars[idx%len(chars)])
+			s.mu.Unlock()
 			idx++
 			time.Sleep(100 * time.Millisecond)
 		}
 	}
 }
 
-func HandleActCommand(args []string, safe bool, cfg *config.Config, systemPrompt string) error {
-	responseContent, err := HandleCall(args, cfg, systemPrompt, logic.MessageTypeCommand)
-	if err != nil {
-		return err
+func (s *spinner) println(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Printf("\r%s\n", line)
+}
+
+func (s *spinner) stop() {
+	s.once.Do(func() {
+		s.done <- true
+		close(s.done)
+	})
+}
+
+func streamHandler(messageType logic.MessageType, progress *spinner) func(string) {
+	if messageType == logic.MessageTypeCommand {
+		tracker := &logic.CodeBlockTracker{}
+		return func(delta string) {
+			for _, path := range tracker.Feed(delta) {
+				progress.println("Generating: " + path)
+			}
+		}
 	}
 
-	return processCodeBlocks(responseContent, safe)
+	started := false
+	return func(delta string) {
+		if !started {
+			progress.stop()
+			fmt.Println()
+			started = true
+		}
+		fmt.Print(delta)
+	}
 }
 
-func HandleVerbalCommand(args []string, cfg *config.Config, systemPrompt string, messageType logic.MessageType) error {
-	responseContent, err := HandleCall(args, cfg, systemPrompt, messageType)
+func HandleActCommand(ctx context.Context, args []string, opts WriteOptions, cfg *config.Config, systemPrompt string) error {
+	responseContent, err := handleCall(ctx, args, cfg, systemPrompt, logic.MessageTypeCommand, !opts.DryRun)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("\n" + responseContent)
-	return nil
+	_, err = writeAndVerify(ctx, responseContent, opts, cfg, systemPrompt)
+	return err
+}
+
+func HandleVerbalCommand(ctx context.Context, args []string, cfg *config.Config, systemPrompt string, messageType logic.MessageType) error {
+	_, err := HandleCall(ctx, args, cfg, systemPrompt, messageType)
+	return err
 }
 
-func HandleGoCommand(cfg *config.Config, systemPrompt string) error {
+func HandleGoCommand(ctx context.Context, opts WriteOptions, cfg *config.Config, systemPrompt string) error {
+	maybeCompact(ctx, cfg)
 
 	messages, err := logic.LoadContextForMessageType(logic.MessageTypeCommand)
 	if err != nil {
@@ -53,17 +102,24 @@ func HandleGoCommand(cfg *config.Config, systemPrompt string) error {
 		messages = []logic.Message{}
 	}
 
-	responseContent, err := callClaudeAPI(messages, cfg, systemPrompt, logic.MessageTypeCommand)
+	responseContent, err := callClaudeAPI(ctx, messages, nil, cfg, systemPrompt, logic.MessageTypeCommand, !opts.DryRun)
 	if err != nil {
 		return err
 	}
 
-	return processCodeBlocks(responseContent, false)
+	_, err = writeAndVerify(ctx, responseContent, opts, cfg, systemPrompt)
+	return err
+}
+
+func HandleCall(ctx context.Context, args []string, cfg *config.Config, systemPrompt string, messageType logic.MessageType) (string, error) {
+	return handleCall(ctx, args, cfg, systemPrompt, messageType, true)
 }
 
-func HandleCall(args []string, cfg *config.Config, systemPrompt string, messageType logic.MessageType) (string, error) {
+func handleCall(ctx context.Context, args []string, cfg *config.Config, systemPrompt string, messageType logic.MessageType, save bool) (string, error) {
 	prompt := strings.Join(args, " ")
 
+	maybeCompact(ctx, cfg)
+
 	contextMessages, err := logic.LoadContextForMessageType(messageType)
 	if err != nil {
 		fmt.Printf("Warning: could not load context: %v\n", err)
@@ -75,32 +131,56 @@ func HandleCall(args []string, cfg *config.Config, systemPrompt string, messageT
 		Content: prompt,
 	}
 
-	messages := append(contextMessages, userMessage)
-
-	responseContent, err := callClaudeAPI(messages, cfg, systemPrompt, messageType)
+	responseContent, err := callClaudeAPI(ctx, contextMessages, &userMessage, cfg, systemPrompt, messageType, save)
 	if err != nil {
+		if ctx.Err() != nil || !save {
+			return "", err
+		}
+		userMessage.Mode = promptMode(systemPrompt)
+		if saveErr := savePendingMessage(userMessage); saveErr != nil {
+			fmt.Printf("Warning: could not save pending prompt: %v\n", saveErr)
+		} else {
+			fmt.Println("Prompt saved as pending. Run 'y retry' to send it again.")
+		}
 		return "", err
 	}
 
 	return responseContent, nil
 }
 
-func callClaudeAPI(messages []logic.Message, cfg *config.Config, systemPrompt string, messageType logic.MessageType) (string, error) {
-	fmt.Printf("Sending request to Claude...\n")
+func callClaudeAPI(ctx context.Context, contextMessages []logic.Message, prompt *logic.Message, cfg *config.Config, systemPrompt string, messageType logic.MessageType, save bool) (string, error) {
+	messages := contextMessages
+	if prompt != nil {
+		messages = append(append([]logic.Message{}, contextMessages...), *prompt)
+	}
 
-	var client api.Client
-	client = &api.ClaudeClient{}
-	client.Init(cfg)
+	fmt.Printf("Sending request to %s...\n", cfg.Provider)
+
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return "", err
+	}
 
 	fmt.Printf("Model: %s\n", client.GetModelName())
 
-	done := make(chan bool)
-	go showProgress(done)
+	outgoing, err := redactSecrets(messages, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkTokenBudget(ctx, client, outgoing, systemPrompt, cfg); err != nil {
+		return "", err
+	}
+
+	progress := startSpinner()
 
-	response, err := client.Call(messages, systemPrompt)
+	response, err := client.Stream(ctx, outgoing, systemPrompt, streamHandler(messageType, progress))
 
-	done <- true
-	close(done)
+	progress.stop()
+
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("request cancelled")
+	}
 
 	if err != nil {
 		return "", err
@@ -109,36 +189,55 @@ func callClaudeAPI(messages []logic.Message, cfg *config.Config, systemPrompt st
 	responseContent := response.Content
 
 	if strings.TrimSpace(responseContent) == "" {
-		return "", fmt.Errorf("error: empty response from Claude API")
+		return "", fmt.Errorf("error: empty response from %s API", cfg.Provider)
+	}
+
+	if !save {
+		fmt.Println("Dry run, exchange not saved to context")
+		return responseContent, nil
 	}
 
 	message := logic.Message{
 		Content: responseContent,
 		Type:    logic.ResponseType(messageType),
 	}
+	if message.Type == logic.MessageTypePlan {
+		message.Steps = logic.ParsePlanSteps(responseContent)
+	}
 
-	updatedMessages := append(messages, message)
-	if err := logic.SaveContext(updatedMessages); err != nil {
+	err = logic.UpdateContext(func(current []logic.Message) ([]logic.Message, error) {
+		if prompt != nil {
+			if idx := pendingIndex(current, *prompt); idx != -1 {
+				current[idx].Pending = false
+				current[idx].Mode = ""
+			} else {
+				sent := *prompt
+				sent.Pending = false
+				sent.Mode = ""
+				current = append(current, sent)
+			}
+		}
+		return append(current, message), nil
+	})
+	if err != nil {
 		fmt.Printf("Warning: could not save context: %v\n", err)
 	}
 
 	return responseContent, nil
 }
 
-func processCodeBlocks(content string, safe bool) error {
-	fmt.Println("Processing response...")
-	var parseErrors []string
-	for _, codeBlock := range logic.ParseCodeBlocks(content) {
-		err := codeBlock.Write(safe)
-		if err != nil {
-			parseErrors = append(parseErrors, fmt.Sprintf("%v", err))
+func pendingIndex(messages []logic.Message, prompt logic.Message) int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Pending && messages[i].Type == prompt.Type && messages[i].Content == prompt.Content {
+			return i
 		}
 	}
+	return -1
+}
 
-	if len(parseErrors) > 0 {
-		return fmt.Errorf("error processing code blocks: %s", strings.Join(parseErrors, "; "))
-	}
-
-	fmt.Println("Done!")
-	return nil
+func savePendingMessage(userMessage logic.Message) error {
+	userMessage.Pending = true
+	return logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		return append(messages, userMessage), nil
+	})
 }
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"yact/api"
+	"yact/config"
+	"yact/config/systemprompt"
+	"yact/logic"
+)
+
+func conversationMessages(messages []logic.Message) []logic.Message {
+	var conversation []logic.Message
+	for _, message := range messages {
+		if message.Type != logic.MessageTypeFile {
+			conversation = append(conversation, message)
+		}
+	}
+	return conversation
+}
+
+func splitForCompaction(messages []logic.Message, keep int) ([]logic.Message, []logic.Message) {
+	conversation := conversationMessages(messages)
+	if len(conversation) <= keep {
+		return nil, conversation
+	}
+	return conversation[:len(conversation)-keep], conversation[len(conversation)-keep:]
+}
+
+func keptPlan(messages []logic.Message) *logic.Message {
+	if idx := logic.LatestPlanIndex(messages); idx != -1 {
+		return &messages[idx]
+	}
+	return nil
+}
+
+func isKeptPlan(message logic.Message, plan *logic.Message) bool {
+	return plan != nil && len(message.Steps) > 0 && message.Content == plan.Content
+}
+
+func summarizable(messages []logic.Message, keep int) []logic.Message {
+	older, _ := splitForCompaction(messages, keep)
+	plan := keptPlan(messages)
+
+	var result []logic.Message
+	for _, message := range older {
+		if !isKeptPlan(message, plan) {
+			result = append(result, message)
+		}
+	}
+	return result
+}
+
+func replaceWithSummary(current []logic.Message, older []logic.Message, plan *logic.Message, summary logic.Message) ([]logic.Message, error) {
+	var compacted []logic.Message
+	matched := 0
+	for _, message := range current {
+		if message.Type == logic.MessageTypeFile || isKeptPlan(message, plan) || matched == len(older) {
+			compacted = append(compacted, message)
+			continue
+		}
+		if message.Type != older[matched].Type || message.Content != older[matched].Content {
+			return nil, fmt.Errorf("context changed while compacting, try again")
+		}
+		if matched == 0 {
+			compacted = append(compacted, summary)
+		}
+		matched++
+	}
+
+	if matched < len(older) {
+		return nil, fmt.Errorf("context changed while compacting, try again")
+	}
+	return compacted, nil
+}
+
+func renderConversation(messages []logic.Message) string {
+	var parts []string
+	for _, message := range messages {
+		parts = append(parts, fmt.Sprintf("[%s]\n%s", message.Type, message.Content))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func compactContext(ctx context.Context, cfg *config.Config, keep int) error {
+	messages, err := logic.LoadContext()
+	if err != nil {
+		return err
+	}
+
+	older := summarizable(messages, keep)
+	plan := keptPlan(messages)
+	if len(older) == 0 {
+		fmt.Println("Nothing to compact")
+		return nil
+	}
+
+	fmt.Printf("Summarizing %d messages...\n", len(older))
+
+	client, err := api.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	outgoing, err := redactSecrets(older, cfg)
+	if err != nil {
+		return err
+	}
+	request := []logic.Message{{
+		Type:    logic.MessageTypeSummary,
+		Content: renderConversation(outgoing),
+	}}
+
+	if err := checkTokenBudget(ctx, client, request, systemprompt.Compact, cfg); err != nil {
+		return err
+	}
+
+	progress := startSpinner()
+	summary, err := client.Call(ctx, request, systemprompt.Compact)
+	progress.stop()
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("request cancelled")
+	}
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(summary.Content) == "" {
+		return fmt.Errorf("error: empty summary from %s API", cfg.Provider)
+	}
+
+	err = logic.UpdateContext(func(current []logic.Message) ([]logic.Message, error) {
+		return replaceWithSummary(current, older, plan, logic.Message{Type: logic.MessageTypeSummary, Content: summary.Content})
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Replaced %d messages with a summary\n", len(older))
+	return nil
+}
+
+func maybeCompact(ctx context.Context, cfg *config.Config) {
+	if cfg.CompactAfter <= 0 {
+		return
+	}
+
+	messages, err := logic.LoadContext()
+	if err != nil {
+		return
+	}
+
+	if len(conversationMessages(messages)) <= cfg.CompactAfter {
+		return
+	}
+
+	if err := compactContext(ctx, cfg, cfg.CompactKeep); err != nil {
+		fmt.Printf("Warning: could not compact context: %v\n", err)
+	}
+}
+
+func HandleCompactCommand(ctx context.Context, args []string, cfg *config.Config) error {
+	keep := cfg.CompactKeep
+	if len(args) > 0 {
+		num, err := strconv.Atoi(args[0])
+		if err != nil || num < 0 {
+			return fmt.Errorf("invalid number: %s", args[0])
+		}
+		keep = num
+	}
+
+	return compactContext(ctx, cfg, keep)
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"yact/logic"
+)
+
+func TestCompactionKeepsPlanAndFileOrder(t *testing.T) {
+	plan := logic.Message{Type: logic.MessageTypePlan, Content: "1. step", Steps: []logic.PlanStep{{Number: 1, Title: "step"}}}
+	messages := []logic.Message{
+		{Type: logic.MessageTypeFile, Path: "a.go", Content: "a"},
+		{Type: logic.MessageTypeQuestion, Content: "q1"},
+		{Type: logic.MessageTypeAnswer, Content: "a1"},
+		{Type: logic.MessageTypeFile, Path: "b.go", Content: "b"},
+		{Type: logic.MessageTypeQuestion, Content: "plan it"},
+		plan,
+		{Type: logic.MessageTypeCommand, Content: "c1"},
+		{Type: logic.MessageTypeAction, Content: "r1"},
+	}
+
+	older := summarizable(messages, 2)
+	wantOlder := []logic.Message{messages[1], messages[2], messages[4]}
+	if !reflect.DeepEqual(older, wantOlder) {
+		t.Fatalf("summarizable() = %+v, want %+v", older, wantOlder)
+	}
+
+	summary := logic.Message{Type: logic.MessageTypeSummary, Content: "summary"}
+	current := append(append([]logic.Message{}, messages...), logic.Message{Type: logic.MessageTypeFile, Path: "c.go", Content: "c"})
+
+	compacted, err := replaceWithSummary(current, older, keptPlan(messages), summary)
+	if err != nil {
+		t.Fatalf("replaceWithSummary: %v", err)
+	}
+
+	want := []logic.Message{messages[0], summary, messages[3], plan, messages[6], messages[7], current[8]}
+	if !reflect.DeepEqual(compacted, want) {
+		t.Errorf("compacted =\n%+v\nwant\n%+v", compacted, want)
+	}
+}
+
+func TestCompactionDetectsConcurrentChanges(t *testing.T) {
+	messages := []logic.Message{
+		{Type: logic.MessageTypeQuestion, Content: "q1"},
+		{Type: logic.MessageTypeAnswer, Content: "a1"},
+		{Type: logic.MessageTypeQuestion, Content: "q2"},
+	}
+	older := summarizable(messages, 1)
+
+	summary := logic.Message{Type: logic.MessageTypeSummary, Content: "summary"}
+	if _, err := replaceWithSummary(messages[1:], older, nil, summary); err == nil {
+		t.Error("replaceWithSummary accepted a context that lost a summarized message")
+	}
+}
@@ -2,6 +2,9 @@ package commands
 
 import (
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"yact/config"
@@ -10,12 +13,30 @@ import (
 func HandleConfigCommand(args []string, cfg *config.Config) error {
 	if len(args) == 0 {
 		fmt.Println("Current configuration:")
-		if cfg.AnthropicAPIKey != "" {
-			fmt.Printf("  anthropic_api_key: %s\n", strings.Repeat("*", len(cfg.AnthropicAPIKey)))
-		} else {
-			fmt.Printf("  anthropic_api_key: %s\n", cfg.AnthropicAPIKey)
+		if projectDir, err := config.FindProjectDir(); err == nil && projectDir != "" {
+			fmt.Printf("  (project overrides from %s)\n", filepath.Join(projectDir, "config"))
 		}
+		fmt.Printf("  provider: %s\n", cfg.Provider)
+		fmt.Printf("  anthropic_api_key: %s\n", strings.Repeat("*", len(cfg.AnthropicAPIKey)))
 		fmt.Printf("  claude_model: %s\n", cfg.ClaudeModel)
+		fmt.Printf("  openai_api_key: %s\n", strings.Repeat("*", len(cfg.OpenAIAPIKey)))
+		fmt.Printf("  openai_model: %s\n", cfg.OpenAIModel)
+		fmt.Printf("  base_url: %s\n", cfg.BaseURL)
+		fmt.Printf("  max_retries: %d\n", cfg.MaxRetries)
+		fmt.Printf("  edit_format: %s\n", cfg.EditFormat)
+		fmt.Printf("  compact_after: %d\n", cfg.CompactAfter)
+		fmt.Printf("  compact_keep: %d\n", cfg.CompactKeep)
+		fmt.Printf("  token_budget: %d\n", cfg.TokenBudget)
+		fmt.Printf("  token_budget_action: %s\n", cfg.TokenBudgetAction)
+		fmt.Printf("  allow_paths: %s\n", strings.Join(cfg.AllowPaths, ","))
+		fmt.Printf("  deny_paths: %s\n", strings.Join(cfg.DenyPaths, ","))
+		fmt.Printf("  redact_action: %s\n", cfg.RedactAction)
+		for _, pattern := range cfg.RedactPatterns {
+			fmt.Printf("  redact_patterns: %s\n", pattern)
+		}
+		for _, command := range cfg.VerifyCommands {
+			fmt.Printf("  verify_commands: %s\n", command)
+		}
 		return nil
 	}
 
@@ -23,11 +44,84 @@ func HandleConfigCommand(args []string, cfg *config.Config) error {
 		key := args[0]
 		value := args[1]
 
+		globalCfg, err := config.LoadGlobal()
+		if err != nil {
+			return fmt.Errorf("error loading global config: %w", err)
+		}
+		cfg = globalCfg
+
 		switch key {
+		case "provider":
+			if value != config.ProviderAnthropic && value != config.ProviderOpenAI {
+				return fmt.Errorf("unknown provider '%s' (expected %s or %s)", value, config.ProviderAnthropic, config.ProviderOpenAI)
+			}
+			cfg.Provider = value
 		case "anthropic_api_key":
 			cfg.AnthropicAPIKey = value
 		case "claude_model":
 			cfg.ClaudeModel = value
+		case "openai_api_key":
+			cfg.OpenAIAPIKey = value
+		case "openai_model":
+			cfg.OpenAIModel = value
+		case "base_url":
+			cfg.BaseURL = value
+		case "max_retries":
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return fmt.Errorf("invalid max_retries: %s", value)
+			}
+			cfg.MaxRetries = retries
+		case "edit_format":
+			if value != config.EditFormatWhole && value != config.EditFormatSearch {
+				return fmt.Errorf("unknown edit format '%s' (expected %s or %s)", value, config.EditFormatWhole, config.EditFormatSearch)
+			}
+			cfg.EditFormat = value
+		case "compact_after", "compact_keep":
+			num, err := strconv.Atoi(value)
+			if err != nil || num < 0 {
+				return fmt.Errorf("invalid %s: %s", key, value)
+			}
+			if key == "compact_after" {
+				cfg.CompactAfter = num
+			} else {
+				cfg.CompactKeep = num
+			}
+		case "token_budget":
+			budget, err := strconv.Atoi(value)
+			if err != nil || budget < 0 {
+				return fmt.Errorf("invalid token_budget: %s", value)
+			}
+			cfg.TokenBudget = budget
+		case "token_budget_action":
+			if value != config.BudgetActionWarn && value != config.BudgetActionRefuse {
+				return fmt.Errorf("unknown token budget action '%s' (expected %s or %s)", value, config.BudgetActionWarn, config.BudgetActionRefuse)
+			}
+			cfg.TokenBudgetAction = value
+		case "allow_paths":
+			cfg.AllowPaths = splitList(value)
+		case "deny_paths":
+			cfg.DenyPaths = splitList(value)
+		case "redact_action":
+			if value != config.RedactActionMask && value != config.RedactActionBlock && value != config.RedactActionOff {
+				return fmt.Errorf("unknown redact action '%s' (expected %s, %s or %s)", value, config.RedactActionMask, config.RedactActionBlock, config.RedactActionOff)
+			}
+			cfg.RedactAction = value
+		case "redact_patterns":
+			if value == "" {
+				cfg.RedactPatterns = nil
+				break
+			}
+			if _, err := regexp.Compile(value); err != nil {
+				return fmt.Errorf("invalid redact pattern: %w", err)
+			}
+			cfg.RedactPatterns = append(cfg.RedactPatterns, value)
+		case "verify_commands":
+			if value == "" {
+				cfg.VerifyCommands = nil
+				break
+			}
+			cfg.VerifyCommands = append(cfg.VerifyCommands, value)
 		default:
 			return fmt.Errorf("unknown config key '%s'", key)
 		}
@@ -36,7 +130,7 @@ func HandleConfigCommand(args []string, cfg *config.Config) error {
 			return fmt.Errorf("error saving config: %w", err)
 		}
 
-		if key == "anthropic_api_key" {
+		if key == "anthropic_api_key" || key == "openai_api_key" {
 			fmt.Printf("Set %s to %s\n", key, strings.Repeat("*", len(value)))
 		} else {
 			fmt.Printf("Set %s to %s\n", key, value)
@@ -49,3 +143,13 @@ func HandleConfigCommand(args []string, cfg *config.Config) error {
 	fmt.Println("  y config <key> <value>      # Set config value")
 	return nil
 }
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
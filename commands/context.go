@@ -7,6 +7,15 @@ import (
 	"yact/logic"
 )
 
+var contextModes = []struct {
+	name        string
+	messageType logic.MessageType
+}{
+	{"act", logic.MessageTypeCommand},
+	{"ask", logic.MessageTypeQuestion},
+	{"plan", logic.MessageTypeObjective},
+}
+
 func HandleContextCommand() error {
 	messages, err := logic.LoadContext()
 	if err != nil {
@@ -18,8 +27,22 @@ func HandleContextCommand() error {
 		return nil
 	}
 
+	totalTokens := 0
+	modeTokens := make([]int, len(contextModes))
 	for i, message := range messages {
 		fmt.Printf("[%d] %s", i, message.Type)
+		if message.Pending {
+			fmt.Printf(" (pending)")
+		}
+		if len(message.Steps) > 0 {
+			done := 0
+			for _, step := range message.Steps {
+				if step.Done {
+					done++
+				}
+			}
+			fmt.Printf(" (%d/%d steps done)", done, len(message.Steps))
+		}
 		if message.Path != "" {
 			fmt.Printf(" - %s", message.Path)
 		} else {
@@ -31,8 +54,25 @@ func HandleContextCommand() error {
 			truncatedContent = strings.ReplaceAll(truncatedContent, "\n", " ")
 			fmt.Printf(" - %s", truncatedContent)
 		}
+		tokens := logic.EstimateTokens(message.Content)
+		totalTokens += tokens
+		fmt.Printf(" (~%d tokens)", tokens)
+
+		var sentIn []string
+		for m, mode := range contextModes {
+			if logic.SentForMessageType(message, mode.messageType) {
+				sentIn = append(sentIn, mode.name)
+				modeTokens[m] += tokens
+			}
+		}
+		fmt.Printf(" [%s]", strings.Join(sentIn, " "))
 		fmt.Println()
 	}
 
+	fmt.Printf("Total: ~%d tokens (estimated)\n", totalTokens)
+	for m, mode := range contextModes {
+		fmt.Printf("Sent by %s: ~%d tokens\n", mode.name, modeTokens[m])
+	}
+
 	return nil
 }
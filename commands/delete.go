@@ -16,18 +16,14 @@ func HandleDelete(args []string) error {
 		return fmt.Errorf("invalid index: %s", args[0])
 	}
 
-	messages, err := logic.LoadContext()
-	if err != nil {
-		return err
-	}
+	err = logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		if idx < 0 || idx >= len(messages) {
+			return nil, fmt.Errorf("index out of range: %d", idx)
+		}
 
-	if idx < 0 || idx >= len(messages) {
-		return fmt.Errorf("index out of range: %d", idx)
-	}
-
-	messages = append(messages[:idx], messages[idx+1:]...)
-
-	if err := logic.SaveContext(messages); err != nil {
+		return append(messages[:idx], messages[idx+1:]...), nil
+	})
+	if err != nil {
 		return err
 	}
 
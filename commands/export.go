@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"yact/config"
+	"yact/logic"
+)
+
+func HandleExportCommand(args []string, format string, redact bool, cfg *config.Config) error {
+	if len(args) > 1 {
+		return fmt.Errorf("export takes at most one file argument")
+	}
+
+	messages, err := logic.LoadContext()
+	if err != nil {
+		return err
+	}
+
+	if redact {
+		redactor, err := logic.NewRedactor(cfg.RedactPatterns)
+		if err != nil {
+			return err
+		}
+
+		var findings []logic.RedactionFinding
+		messages, findings = redactor.RedactMessages(messages)
+		for _, finding := range findings {
+			fmt.Fprintf(os.Stderr, "Redacted in export: %s: %s x%d\n", finding.Path, finding.Detector, finding.Count)
+		}
+	}
+
+	session, err := logic.ActiveSession()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case "md", "markdown":
+		data = []byte(logic.RenderMarkdown(session, messages))
+	case "json":
+		data, err = logic.ExportJSON(session, messages)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown export format '%s' (expected md or json)", format)
+	}
+
+	if len(args) == 0 {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(args[0], data, 0644); err != nil {
+		return fmt.Errorf("error writing file %s: %w", args[0], err)
+	}
+	fmt.Printf("Exported %d messages to %s\n", len(messages), args[0])
+	return nil
+}
+
+func HandleImportCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("file argument required")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %w", args[0], err)
+	}
+
+	imported, err := logic.ParseExport(data)
+	if err != nil {
+		return err
+	}
+
+	err = logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		return append(messages, imported...), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d messages from %s\n", len(imported), args[0])
+	return nil
+}
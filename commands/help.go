@@ -12,24 +12,65 @@ func ShowHelp() {
 	fmt.Println("  y bash [prompt]         # Generate a bash script file")
 	fmt.Println("  y ask [question]        # Ask questions about the codebase")
 	fmt.Println("  y plan [prompt]         # Get a plan for implementation")
-	fmt.Println("  y step <index>          # Implement a specific step from the plan")
-	fmt.Println("  y go                    # Execute the plan (alias for 'act Do it.')")
-	fmt.Println("  y accept                # Accept last plan as user message")
-	fmt.Println("  y read <file>           # Add file reference to prompt")
+	fmt.Println("  y steps                 # List the steps of the latest plan and their status")
+	fmt.Println("  y step <index>          # Implement a specific step from the plan and mark it done")
+	fmt.Println("  y go [--all]            # Execute the plan (--all: remaining steps one by one)")
+	fmt.Println("  y undo [num]            # Restore files overwritten by the last num writes (default: 1)")
+	fmt.Println("  y redo [num]            # Re-apply the last num undone writes (default: 1)")
+	fmt.Println("  y retry                 # Resend the last prompt that failed")
+	fmt.Println("  y accept [--edit]       # Accept last plan as user message")
+	fmt.Println("  y read <file|dir>       # Add file references to prompt (dirs and ** are walked)")
+	fmt.Println("  y unread <glob>         # Remove matching files from context (--missing: files deleted on disk)")
+	fmt.Println("  y run -- <cmd...>       # Run a command and add its output and exit code to context")
 	fmt.Println("  y context               # List all messages in context")
 	fmt.Println("  y pop [num]             # Remove last num messages (default: 1)")
 	fmt.Println("  y del <idx>             # Remove message at index")
 	fmt.Println("  y reload                # Reload file contents from disk")
 	fmt.Println("  y reset                 # Reload file contents from disk, then remove other messages")
-	fmt.Println("  y new                   # Create a new context")
+	fmt.Println("  y compact [keep]        # Summarize older messages, keeping files and the last keep messages")
+	fmt.Println("  y export [file]         # Export the context as Markdown (or --format json)")
+	fmt.Println("  y import <file>         # Append messages from an exported JSON session")
+	fmt.Println("  y checkpoint <name>     # Save the current conversation state")
+	fmt.Println("  y branch [name] [from]  # List branches, or branch off the current state (or from)")
+	fmt.Println("  y checkout <name>       # Switch to a branch, or branch off a checkpoint")
+	fmt.Println("  y init                  # Create a .yact project directory here")
+	fmt.Println("  y new                   # Clear the context of the active session")
+	fmt.Println("  y session [cmd]         # Manage named sessions: new, list, switch, rm, rename")
 	fmt.Println("  y last                  # Show last AI response")
 	fmt.Println("  y config                # Show current configuration")
 	fmt.Println("  y config <key> <value>  # Set configuration value")
 	fmt.Println()
 	fmt.Println("Options:")
-	fmt.Println("  --safe, -s       Add .new suffix to generated files")
+	fmt.Println("  --safe, -s          Add .new suffix to generated files")
+	fmt.Println("  --dry-run, --diff   Show diffs of generated files without writing them")
+	fmt.Println("  --interactive, -i   Review each generated file: accept, reject, edit or write as .new")
+	fmt.Println("  --format            Export format: md (default) or json")
+	fmt.Println("  --no-redact         With export, keep secrets in read files unmasked")
+	fmt.Println("  --edit-format       Edit format for act, step and go: whole or search-replace")
+	fmt.Println("  --missing           With unread, remove files that no longer exist")
+	fmt.Println("  --edit              With accept, edit the plan in $EDITOR first")
+	fmt.Println("  --all               With go, execute the remaining plan steps one by one")
+	fmt.Println("  --fix N             Let the model repair failed verify commands up to N times")
+	fmt.Println("  --git-diff [base]   With read, add the diff against base (default: HEAD)")
+	fmt.Println("  --staged            With read, use the staged changes")
+	fmt.Println("  --changed [base]    With read, add every file changed against base")
 	fmt.Println()
 	fmt.Println("Configuration keys:")
+	fmt.Println("  provider            API provider: anthropic (default) or openai")
 	fmt.Println("  anthropic_api_key   Claude API key")
 	fmt.Println("  claude_model        Claude model name")
+	fmt.Println("  openai_api_key      API key for the OpenAI-compatible endpoint")
+	fmt.Println("  openai_model        Model name for the OpenAI-compatible endpoint")
+	fmt.Println("  base_url            Base URL of the OpenAI-compatible endpoint")
+	fmt.Println("  edit_format         whole (default) or search-replace")
+	fmt.Println("  allow_paths         Comma-separated globs generated files must match")
+	fmt.Println("  deny_paths          Comma-separated globs generated files must not match")
+	fmt.Println("  compact_after       Compact automatically above this many non-file messages (0: off)")
+	fmt.Println("  compact_keep        Recent messages kept verbatim when compacting (default: 4)")
+	fmt.Println("  token_budget        Input token budget checked before each request (0: off)")
+	fmt.Println("  token_budget_action warn (default) or refuse when over the budget")
+	fmt.Println("  redact_action       mask (default), block or off for secrets in read files")
+	fmt.Println("  redact_patterns     Add a regex treated as a secret (empty value clears)")
+	fmt.Println("  verify_commands     Add a shell command run after writes (empty value clears)")
+	fmt.Println("  max_retries         Retries on rate limit, overload and server errors (default: 3)")
 }
@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"fmt"
+	"yact/config"
+)
+
+func HandleInitCommand() error {
+	dir, err := config.InitProjectDir()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Initialized project directory %s\n", dir)
+	fmt.Println("Conversations in this directory and below are now stored there.")
+	fmt.Println("Put project settings in .yact/config to override the global config.")
+	return nil
+}
@@ -28,9 +28,15 @@ func HandleLastCommand(filePath string) error {
 		return fmt.Errorf("error reading file %s: %w", filePath, err)
 	}
 
-	contextMessages[lastIndex].Content = string(content)
-
-	if err := logic.SaveContext(contextMessages); err != nil {
+	err = logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		if len(messages) == 0 {
+			return nil, fmt.Errorf("no previous assistant message found")
+		}
+
+		messages[len(messages)-1].Content = string(content)
+		return messages, nil
+	})
+	if err != nil {
 		return fmt.Errorf("error saving context: %w", err)
 	}
 
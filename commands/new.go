@@ -6,7 +6,9 @@ import (
 )
 
 func HandleNewCommand() error {
-	err := logic.SaveContext(make([]logic.Message, 0))
+	err := logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		return make([]logic.Message, 0), nil
+	})
 	fmt.Println("New context created")
 	return err
 }
@@ -7,11 +7,6 @@ import (
 )
 
 func HandlePop(args []string) error {
-	messages, err := logic.LoadContext()
-	if err != nil {
-		return err
-	}
-
 	numToPop := 1
 	if len(args) > 0 {
 		num, err := strconv.Atoi(args[0])
@@ -21,13 +16,14 @@ func HandlePop(args []string) error {
 		numToPop = num
 	}
 
-	if numToPop > len(messages) {
-		numToPop = len(messages)
-	}
-
-	messages = messages[:len(messages)-numToPop]
+	err := logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		if numToPop > len(messages) {
+			numToPop = len(messages)
+		}
 
-	if err := logic.SaveContext(messages); err != nil {
+		return messages[:len(messages)-numToPop], nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -1,67 +1,166 @@
 package commands
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
 	"yact/logic"
 )
 
-func HandleReadCommand(args []string) error {
+const (
+	maxGitDiffSize     = 100 * 1024
+	maxGitChangedFiles = 100
+)
+
+type GitReadOptions struct {
+	Diff    bool
+	Staged  bool
+	Changed bool
+}
+
+func HandleReadCommand(ctx context.Context, args []string, git GitReadOptions) error {
+	if git.Diff || git.Staged || git.Changed {
+		return readGitChanges(ctx, args, git)
+	}
+
 	if len(args) < 1 {
-		fmt.Println("Usage: y read <file> [<file2> ...]")
+		fmt.Println("Usage: y read <file|dir|pattern> [...]")
 		return fmt.Errorf("missing file argument")
 	}
 
-	for _, pattern := range args {
-		matches, err := filepath.Glob(pattern)
-		if err != nil {
-			return fmt.Errorf("error matching pattern %s: %w", pattern, err)
-		}
+	collection, err := logic.CollectFiles(args)
+	if err != nil {
+		return err
+	}
+
+	return addCollectedFiles(collection)
+}
+
+func readGitChanges(ctx context.Context, args []string, git GitReadOptions) error {
+	if len(args) > 1 {
+		fmt.Println("Usage: y read --git-diff|--staged|--changed [base]")
+		return fmt.Errorf("too many arguments")
+	}
+
+	base := "HEAD"
+	if len(args) == 1 {
+		base = args[0]
+	}
 
-		if len(matches) == 0 {
-			fmt.Printf("No files found matching pattern: %s\n", pattern)
-			continue
+	if git.Diff || !git.Changed {
+		if err := readGitDiff(ctx, base, git.Staged); err != nil {
+			return err
 		}
+	}
 
-		for _, filePath := range matches {
-			info, err := os.Stat(filePath)
-			if err != nil {
-				fmt.Printf("Error accessing %s: %v\n", filePath, err)
-				continue
-			}
+	if !git.Changed {
+		return nil
+	}
 
-			if info.IsDir() {
-				fmt.Printf("Skipping directory: %s\n", filePath)
-				continue
-			}
-			messages, err := logic.LoadContext()
-			if err != nil {
-				return err
-			}
-			content, err := logic.ReadAsCodeBlock(filePath)
-			if err != nil {
-				return err
+	files, err := logic.GitChangedFiles(ctx, base, git.Staged)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("No changed files")
+		return nil
+	}
+	if len(files) > maxGitChangedFiles {
+		return fmt.Errorf("%d files changed, more than the limit of %d; read them selectively or use --git-diff", len(files), maxGitChangedFiles)
+	}
+
+	return addCollectedFiles(logic.CollectListedFiles(files))
+}
+
+func readGitDiff(ctx context.Context, base string, staged bool) error {
+	result, err := logic.GitDiff(ctx, base, staged)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(result.Output) == "" {
+		fmt.Printf("No changes: %s\n", result.Command)
+		return nil
+	}
+
+	message := logic.Message{
+		Type:    logic.MessageTypeOutput,
+		Path:    result.Command,
+		Content: result.AsMessageContent(maxGitDiffSize),
+	}
+
+	err = logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		var kept []logic.Message
+		for _, existing := range messages {
+			if existing.Type != logic.MessageTypeOutput || existing.Path != message.Path {
+				kept = append(kept, existing)
 			}
+		}
+		return append(kept, message), nil
+	})
+	if err != nil {
+		return err
+	}
 
+	if len(result.Output) > maxGitDiffSize {
+		fmt.Printf("Diff truncated from %d to %d bytes\n", len(result.Output), maxGitDiffSize)
+	}
+	fmt.Printf("Added %s (~%d tokens)\n", result.Command, logic.EstimateTokens(message.Content))
+	return nil
+}
+
+func addCollectedFiles(collection *logic.FileCollection) error {
+	added := 0
+	tokens := 0
+	err := logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		for _, filePath := range collection.Files {
 			if hasMessageWithPath(messages, filePath) {
 				fmt.Printf("Skipping: %s\n", filePath)
 				continue
-			} else {
-				fmt.Printf("Reading: %s\n", filePath)
 			}
 
-			messages = append(messages, logic.Message{Type: logic.MessageTypeFile, Path: filePath, Content: content})
-			err2 := logic.SaveContext(messages)
-			if err2 != nil {
-				return err2
+			content, err := logic.ReadAsCodeBlock(filePath)
+			if err != nil {
+				return nil, err
 			}
+
+			fmt.Printf("Reading: %s\n", filePath)
+			messages = append(messages, logic.Message{Type: logic.MessageTypeFile, Path: filePath, Content: content})
+			added++
+			tokens += logic.EstimateTokens(content)
 		}
+
+		return messages, nil
+	})
+	if err != nil {
+		return err
 	}
 
+	fmt.Printf("Added %d file(s), ~%d tokens\n", added, tokens)
+	if skipped := collectionSkips(collection); skipped != "" {
+		fmt.Printf("Skipped: %s\n", skipped)
+	}
 	return nil
 }
 
+func collectionSkips(collection *logic.FileCollection) string {
+	var parts []string
+	counts := []struct {
+		count int
+		label string
+	}{
+		{collection.Ignored, "ignored"},
+		{collection.Generated, "vendored or generated"},
+		{collection.Binary, "binary"},
+		{collection.TooLarge, "too large"},
+	}
+	for _, entry := range counts {
+		if entry.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", entry.count, entry.label))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
 func hasMessageWithPath(messages []logic.Message, path string) bool {
 
 	for _, message := range messages {
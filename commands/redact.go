@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"fmt"
+
+	"yact/config"
+	"yact/logic"
+)
+
+func redactSecrets(messages []logic.Message, cfg *config.Config) ([]logic.Message, error) {
+	if cfg.RedactAction == config.RedactActionOff {
+		return messages, nil
+	}
+
+	redactor, err := logic.NewRedactor(cfg.RedactPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted, findings := redactor.RedactMessages(messages)
+	if len(findings) == 0 {
+		return messages, nil
+	}
+
+	total := 0
+	for _, finding := range findings {
+		total += finding.Count
+	}
+
+	if cfg.RedactAction == config.RedactActionBlock {
+		fmt.Printf("Found %d possible secret(s) in context:\n", total)
+		printFindings(findings)
+		return nil, fmt.Errorf("request blocked because the context contains possible secrets; remove them or set redact_action to mask")
+	}
+
+	fmt.Printf("Redacted %d possible secret(s) before sending:\n", total)
+	printFindings(findings)
+	return redacted, nil
+}
+
+func printFindings(findings []logic.RedactionFinding) {
+	for _, finding := range findings {
+		fmt.Printf("  %s: %s x%d\n", finding.Path, finding.Detector, finding.Count)
+	}
+}
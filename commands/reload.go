@@ -2,16 +2,31 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"yact/logic"
 )
 
 func HandleReload() ([]logic.Message, error) {
-	messages, err := logic.LoadContext()
+	var newMessages []logic.Message
+	var reloadErrors []string
+
+	err := logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		newMessages, reloadErrors = reloadMessages(messages)
+		return newMessages, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if len(reloadErrors) > 0 {
+		return nil, fmt.Errorf("reloaded context with errors: %s", strings.Join(reloadErrors, "; "))
+	}
+	fmt.Println("Context files reloaded")
+	return newMessages, nil
+}
+
+func reloadMessages(messages []logic.Message) ([]logic.Message, []string) {
 	var newMessages []logic.Message
 	seenPaths := make(map[string]bool)
 	var reloadErrors []string
@@ -24,7 +39,7 @@ func HandleReload() ([]logic.Message, error) {
 
 			content, err := logic.ReadAsCodeBlock(message.Path)
 			if err != nil {
-				reloadErrors = append(reloadErrors, fmt.Sprintf("could not reload %s: %v", message.Path, err))
+				reloadErrors = appendReloadError(reloadErrors, message.Path, err)
 				continue
 			}
 
@@ -36,7 +51,17 @@ func HandleReload() ([]logic.Message, error) {
 					continue
 				}
 
-				newMessages = append(newMessages, logic.Message{Type: logic.MessageTypeFile, Path: block.Path, Content: logic.AsCodeBlock(block.Path, block.Content)})
+				content := logic.AsCodeBlock(block.Path, block.Content)
+				if block.IsEdit() {
+					var err error
+					content, err = logic.ReadAsCodeBlock(block.Path)
+					if err != nil {
+						reloadErrors = appendReloadError(reloadErrors, block.Path, err)
+						continue
+					}
+				}
+
+				newMessages = append(newMessages, logic.Message{Type: logic.MessageTypeFile, Path: block.Path, Content: content})
 				seenPaths[block.Path] = true
 			}
 		} else {
@@ -44,13 +69,13 @@ func HandleReload() ([]logic.Message, error) {
 		}
 	}
 
-	if err := logic.SaveContext(newMessages); err != nil {
-		return nil, err
-	}
+	return newMessages, reloadErrors
+}
 
-	if len(reloadErrors) > 0 {
-		return nil, fmt.Errorf("reloaded context with errors: %s", strings.Join(reloadErrors, "; "))
+func appendReloadError(reloadErrors []string, path string, err error) []string {
+	if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+		fmt.Printf("Dropped %s, the file no longer exists\n", path)
+		return reloadErrors
 	}
-	fmt.Println("Context files reloaded")
-	return newMessages, nil
+	return append(reloadErrors, fmt.Sprintf("could not reload %s: %v", path, err))
 }
@@ -2,24 +2,32 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 	"yact/logic"
 )
 
 func HandleResetCommand() error {
-	messages, err := HandleReload()
-	if err != nil {
-		return err
-	}
+	var reloadErrors []string
+
+	err := logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		var reloaded []logic.Message
+		reloaded, reloadErrors = reloadMessages(messages)
 
-	var fileMessages []logic.Message
-	for _, message := range messages {
-		if message.Type == logic.MessageTypeFile {
-			fileMessages = append(fileMessages, message)
+		var fileMessages []logic.Message
+		for _, message := range reloaded {
+			if message.Type == logic.MessageTypeFile {
+				fileMessages = append(fileMessages, message)
+			}
 		}
+		return fileMessages, nil
+	})
+	if err != nil {
+		return err
 	}
 
-	err = logic.SaveContext(fileMessages)
-
 	fmt.Println("Context reset")
-	return err
+	if len(reloadErrors) > 0 {
+		return fmt.Errorf("reset context with errors: %s", strings.Join(reloadErrors, "; "))
+	}
+	return nil
 }
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"yact/config"
+	"yact/config/systemprompt"
+	"yact/logic"
+)
+
+func HandleRetryCommand(ctx context.Context, opts WriteOptions, cfg *config.Config) error {
+	contextMessages, err := logic.LoadContext()
+	if err != nil {
+		return fmt.Errorf("error loading context: %w", err)
+	}
+
+	if len(contextMessages) == 0 || !contextMessages[len(contextMessages)-1].Pending {
+		return fmt.Errorf("no pending prompt to retry")
+	}
+
+	pending := contextMessages[len(contextMessages)-1]
+	messageType := pending.Type
+
+	systemPrompt, ok := modePrompt(pending.Mode)
+	if !ok {
+		switch messageType {
+		case logic.MessageTypeCommand:
+			systemPrompt = ActPrompt(cfg.EditFormat)
+		case logic.MessageTypeQuestion:
+			systemPrompt = systemprompt.Ask
+		case logic.MessageTypeObjective:
+			systemPrompt = systemprompt.Plan
+		default:
+			return fmt.Errorf("cannot retry message of type %s", messageType)
+		}
+	}
+
+	messages, err := logic.LoadContextForMessageType(messageType)
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 || !messages[len(messages)-1].Pending {
+		return fmt.Errorf("no pending prompt to retry")
+	}
+	prompt := messages[len(messages)-1]
+
+	save := messageType != logic.MessageTypeCommand || !opts.DryRun
+	responseContent, err := callClaudeAPI(ctx, messages[:len(messages)-1], &prompt, cfg, systemPrompt, messageType, save)
+	if err != nil {
+		return err
+	}
+
+	if messageType == logic.MessageTypeCommand {
+		_, err = writeAndVerify(ctx, responseContent, opts, cfg, systemPrompt)
+		return err
+	}
+	return nil
+}
+
+func ActPrompt(editFormat string) string {
+	if editFormat == config.EditFormatSearch {
+		return systemprompt.Edit
+	}
+	return systemprompt.Act
+}
+
+var promptModes = map[string]string{
+	"act":  systemprompt.Act,
+	"edit": systemprompt.Edit,
+	"bash": systemprompt.Bash,
+	"ask":  systemprompt.Ask,
+	"plan": systemprompt.Plan,
+}
+
+func promptMode(systemPrompt string) string {
+	for mode, prompt := range promptModes {
+		if prompt == systemPrompt {
+			return mode
+		}
+	}
+	return ""
+}
+
+func modePrompt(mode string) (string, bool) {
+	prompt, ok := promptModes[mode]
+	return prompt, ok
+}
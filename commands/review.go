@@ -0,0 +1,230 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"yact/logic"
+)
+
+type reviewDecision string
+
+const (
+	decisionAccept reviewDecision = "accepted"
+	decisionReject reviewDecision = "rejected"
+	decisionEdit   reviewDecision = "edited"
+	decisionNew    reviewDecision = "written as .new"
+)
+
+func openTerminal() (*os.File, error) {
+	stat, err := os.Stdin.Stat()
+	if err == nil && (stat.Mode()&os.ModeCharDevice) != 0 {
+		return os.Stdin, nil
+	}
+	return os.Open("/dev/tty")
+}
+
+func editInEditor(codeBlock logic.CodeBlock) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "yact-*-"+filepath.Base(codeBlock.Path))
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(codeBlock.Content); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	editorArgs := strings.Fields(editor)
+	cmd := exec.Command(editorArgs[0], append(editorArgs[1:], tmp.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if tty, err := openTerminal(); err == nil {
+		cmd.Stdin = tty
+		if tty != os.Stdin {
+			defer tty.Close()
+		}
+	}
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func readAnswer(ctx context.Context, reader *bufio.Reader) (string, error) {
+	type result struct {
+		answer string
+		err    error
+	}
+	answers := make(chan result, 1)
+	go func() {
+		answer, err := reader.ReadString('\n')
+		answers <- result{answer, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case r := <-answers:
+		return r.answer, r.err
+	}
+}
+
+func askDecision(ctx context.Context, reader *bufio.Reader, path string) (reviewDecision, bool, error) {
+	for {
+		fmt.Printf("%s: [a]ccept, [r]eject, [e]dit, write as .[n]ew, [q]uit (reject rest)? ", path)
+		answer, err := readAnswer(ctx, reader)
+		if err != nil {
+			return decisionReject, true, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "accept", "y", "yes":
+			return decisionAccept, false, nil
+		case "r", "reject":
+			return decisionReject, false, nil
+		case "e", "edit":
+			return decisionEdit, false, nil
+		case "n", "new":
+			return decisionNew, false, nil
+		case "q", "quit":
+			return decisionReject, true, nil
+		}
+	}
+}
+
+func reviewCodeBlocks(ctx context.Context, batch *logic.WriteBatch, codeBlocks []logic.CodeBlock, opts WriteOptions) []string {
+	var reviewErrors []string
+
+	tty, err := openTerminal()
+	if err != nil {
+		return []string{fmt.Sprintf("interactive mode needs a terminal: %v", err)}
+	}
+	if tty != os.Stdin {
+		defer tty.Close()
+	}
+	reader := bufio.NewReader(tty)
+
+	decisions := make(map[string]reviewDecision)
+	edits := make(map[string]string)
+	var order []string
+	quit := false
+
+	for _, codeBlock := range codeBlocks {
+		if ctx.Err() != nil {
+			return reviewErrors
+		}
+
+		decision := decisionReject
+		if !quit {
+			diff, _, err := codeBlockDiff(codeBlock)
+			if err != nil {
+				reviewErrors = append(reviewErrors, fmt.Sprintf("%v", err))
+				continue
+			}
+			if diff == "" {
+				fmt.Printf("Unchanged: %s\n", codeBlock.Path)
+				continue
+			}
+			fmt.Print(colorizeDiff(diff))
+
+			decision, quit, err = askDecision(ctx, reader, codeBlock.Path)
+			if ctx.Err() != nil {
+				return reviewErrors
+			}
+			if err != nil {
+				reviewErrors = append(reviewErrors, fmt.Sprintf("error reading answer: %v", err))
+			}
+		}
+
+		safe := opts.Safe
+		switch decision {
+		case decisionReject:
+			fmt.Printf("Rejected: %s\n", codeBlock.Path)
+		case decisionEdit:
+			content, err := editInEditor(codeBlock)
+			if ctx.Err() != nil {
+				return reviewErrors
+			}
+			if err != nil {
+				reviewErrors = append(reviewErrors, fmt.Sprintf("%v", err))
+				decision = decisionReject
+				break
+			}
+			edits[codeBlock.Path] = logic.UnifiedDiff("generated/"+codeBlock.Path, "edited/"+codeBlock.Path, codeBlock.Content, content)
+			codeBlock.Content = content
+		case decisionNew:
+			safe = true
+		}
+
+		if decision != decisionReject {
+			if err := batch.Write(codeBlock, safe); err != nil {
+				reviewErrors = append(reviewErrors, fmt.Sprintf("%v", err))
+			}
+		}
+
+		decisions[codeBlock.Path] = decision
+		order = append(order, codeBlock.Path)
+	}
+
+	if err := saveReviewFeedback(order, decisions, edits); err != nil {
+		fmt.Printf("Warning: could not save review feedback: %v\n", err)
+	}
+
+	return reviewErrors
+}
+
+func reviewFeedback(order []string, decisions map[string]reviewDecision, edits map[string]string) string {
+	var notes []string
+	for _, path := range order {
+		decision := decisions[path]
+		diff := edits[path]
+		if decision == decisionAccept || (decision == decisionEdit && diff == "") {
+			continue
+		}
+
+		note := fmt.Sprintf("- %s: %s", path, decision)
+		if decision == decisionEdit {
+			fence := logic.MarkdownFence(diff)
+			note += fmt.Sprintf(", changes made to your version:\n%sdiff\n%s%s", fence, diff, fence)
+		}
+		notes = append(notes, note)
+	}
+
+	if len(notes) == 0 {
+		return ""
+	}
+	return "Review of the previous response (files not listed were accepted as is):\n" + strings.Join(notes, "\n")
+}
+
+func saveReviewFeedback(order []string, decisions map[string]reviewDecision, edits map[string]string) error {
+	feedback := reviewFeedback(order, decisions, edits)
+	if feedback == "" {
+		return nil
+	}
+
+	return logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		return append(messages, logic.Message{Type: logic.MessageTypeCommand, Content: feedback}), nil
+	})
+}
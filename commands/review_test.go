@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"testing"
+
+	"yact/logic"
+)
+
+func TestReviewFeedback(t *testing.T) {
+	diff := logic.UnifiedDiff("generated/b.go", "edited/b.go", "x := 1\n", "x := 2\n")
+	order := []string{"a.go", "b.go", "c.go", "d.go", "e.go"}
+	decisions := map[string]reviewDecision{
+		"a.go": decisionAccept,
+		"b.go": decisionEdit,
+		"c.go": decisionReject,
+		"d.go": decisionEdit,
+		"e.go": decisionNew,
+	}
+	edits := map[string]string{"b.go": diff, "d.go": ""}
+
+	want := "Review of the previous response (files not listed were accepted as is):\n" +
+		"- b.go: edited, changes made to your version:\n```diff\n" +
+		"--- generated/b.go\n+++ edited/b.go\n@@ -1,1 +1,1 @@\n-x := 1\n+x := 2\n```\n" +
+		"- c.go: rejected\n" +
+		"- e.go: written as .new"
+	if got := reviewFeedback(order, decisions, edits); got != want {
+		t.Errorf("reviewFeedback() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := reviewFeedback([]string{"a.go"}, map[string]reviewDecision{"a.go": decisionAccept}, nil); got != "" {
+		t.Errorf("reviewFeedback() = %q for an all-accepted review, want empty", got)
+	}
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"yact/logic"
+)
+
+const maxRunOutput = 32 * 1024
+
+func HandleRunCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Println("Usage: y run -- <command> [<arg> ...]")
+		return fmt.Errorf("missing command")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	result, err := logic.RunCommand(ctx, cwd, os.Stdout, args[0], args[1:]...)
+	if err != nil {
+		return err
+	}
+
+	message := logic.Message{
+		Type:    logic.MessageTypeOutput,
+		Path:    strings.Join(args, " "),
+		Content: result.AsMessageContent(maxRunOutput),
+	}
+
+	err = logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		return append(messages, message), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(result.Output) > maxRunOutput {
+		fmt.Printf("Output truncated to %d bytes in context\n", maxRunOutput)
+	}
+	fmt.Printf("Captured output of '%s' (exit code %d)\n", message.Path, result.ExitCode)
+	return nil
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"yact/logic"
+)
+
+func printSessionUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  y session                      # Show the active session")
+	fmt.Println("  y session new <name>           # Create a session and switch to it")
+	fmt.Println("  y session list                 # List all sessions")
+	fmt.Println("  y session switch <name>        # Switch to another session")
+	fmt.Println("  y session rm <name>            # Remove a session")
+	fmt.Println("  y session rename <old> <new>   # Rename a session")
+}
+
+func HandleSessionCommand(args []string) error {
+	if len(args) == 0 {
+		active, err := logic.ActiveSession()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Active session: %s\n", active)
+		return nil
+	}
+
+	switch {
+	case args[0] == "new" && len(args) == 2:
+		if err := logic.CreateSession(args[1]); err != nil {
+			return err
+		}
+		if err := logic.SetActiveSession(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Created and switched to session %s\n", args[1])
+	case args[0] == "list" && len(args) == 1:
+		return listSessions()
+	case args[0] == "switch" && len(args) == 2:
+		if err := logic.SetActiveSession(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Switched to session %s\n", args[1])
+	case args[0] == "rm" && len(args) == 2:
+		if err := logic.RemoveSession(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed session %s\n", args[1])
+	case args[0] == "rename" && len(args) == 3:
+		if err := logic.RenameSession(args[1], args[2]); err != nil {
+			return err
+		}
+		fmt.Printf("Renamed session %s to %s\n", args[1], args[2])
+	default:
+		printSessionUsage()
+		return fmt.Errorf("invalid session command")
+	}
+
+	return nil
+}
+
+func listSessions() error {
+	sessions, err := logic.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	active, err := logic.ActiveSession()
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		marker := " "
+		if session == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, session)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"yact/config"
+	"yact/logic"
+)
+
+func loadLatestPlan() (logic.Message, error) {
+	messages, err := logic.LoadContext()
+	if err != nil {
+		return logic.Message{}, fmt.Errorf("error loading context: %w", err)
+	}
+
+	idx := logic.LatestPlanIndex(messages)
+	if idx == -1 {
+		return logic.Message{}, fmt.Errorf("no plan with numbered steps found; create one with 'y plan'")
+	}
+	return messages[idx], nil
+}
+
+func HandleStepsCommand() error {
+	plan, err := loadLatestPlan()
+	if err != nil {
+		return err
+	}
+
+	done := 0
+	for _, step := range plan.Steps {
+		mark := " "
+		if step.Done {
+			mark = "x"
+			done++
+		}
+		fmt.Printf("[%s] %d. %s\n", mark, step.Number, step.Title)
+		if len(step.Files) > 0 {
+			fmt.Printf("      Files: %s\n", strings.Join(step.Files, ", "))
+		}
+	}
+	fmt.Printf("%d of %d steps done\n", done, len(plan.Steps))
+	return nil
+}
+
+func stepPrompt(step logic.PlanStep) string {
+	prompt := fmt.Sprintf("Implement step %d of the plan:\n\n%s\n\n", step.Number, step.Description)
+	if len(step.Files) > 0 {
+		prompt += fmt.Sprintf("Only modify these files: %s. ", strings.Join(step.Files, ", "))
+	}
+	return prompt + "Make no other changes."
+}
+
+func runPlanStep(ctx context.Context, plan logic.Message, step logic.PlanStep, opts WriteOptions, cfg *config.Config, systemPrompt string) (bool, error) {
+	fmt.Printf("Step %d: %s\n", step.Number, step.Title)
+
+	responseContent, err := handleCall(ctx, []string{stepPrompt(step)}, cfg, systemPrompt, logic.MessageTypeCommand, !opts.DryRun)
+	if err != nil {
+		return false, err
+	}
+
+	written, err := writeAndVerify(ctx, responseContent, opts, cfg, systemPrompt)
+	if err != nil {
+		return false, err
+	}
+
+	if opts.DryRun {
+		fmt.Printf("Dry run, step %d not marked done\n", step.Number)
+		return false, nil
+	}
+	if written == 0 {
+		fmt.Printf("No files written, step %d not marked done\n", step.Number)
+		return false, nil
+	}
+
+	if err := logic.MarkStepDone(step.Number, plan.Content); err != nil {
+		return false, fmt.Errorf("error marking step %d done: %w", step.Number, err)
+	}
+	fmt.Printf("Step %d marked done\n", step.Number)
+	return true, nil
+}
+
+func HandleStepCommand(ctx context.Context, args []string, opts WriteOptions, cfg *config.Config, systemPrompt string) error {
+	number, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid step number: %s", args[0])
+	}
+
+	plan, err := loadLatestPlan()
+	if err != nil {
+		stepArgs := []string{"implement", "step", args[0], ". Make no other changes."}
+		return HandleActCommand(ctx, stepArgs, opts, cfg, systemPrompt)
+	}
+
+	idx := logic.FindPlanStep(plan.Steps, number)
+	if idx == -1 {
+		return fmt.Errorf("the plan has no step %d; run 'y steps' to list them", number)
+	}
+
+	_, err = runPlanStep(ctx, plan, plan.Steps[idx], opts, cfg, systemPrompt)
+	return err
+}
+
+func HandleGoAllCommand(ctx context.Context, opts WriteOptions, cfg *config.Config, systemPrompt string) error {
+	plan, err := loadLatestPlan()
+	if err != nil {
+		return err
+	}
+
+	var pending []logic.PlanStep
+	for _, step := range plan.Steps {
+		if !step.Done {
+			pending = append(pending, step)
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Println("All plan steps are done")
+		return nil
+	}
+
+	for i, step := range pending {
+		done, err := runPlanStep(ctx, plan, step, opts, cfg, systemPrompt)
+		if err != nil {
+			return fmt.Errorf("step %d failed: %w", step.Number, err)
+		}
+		if !done && !opts.DryRun {
+			return fmt.Errorf("stopped after step %d, %d step(s) remaining", step.Number, len(pending)-i)
+		}
+	}
+
+	fmt.Printf("Executed %d step(s)\n", len(pending))
+	return nil
+}
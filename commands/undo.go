@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"yact/logic"
+)
+
+func parseCount(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 1 {
+		return 0, fmt.Errorf("invalid number: %s", args[0])
+	}
+	return count, nil
+}
+
+func printJournalEntries(verb string, entries []logic.JournalEntry) {
+	for _, entry := range entries {
+		fmt.Printf("%s: %s (%s)\n", verb, entry.Label, entry.Time.Format("2006-01-02 15:04:05"))
+		for _, file := range entry.Files {
+			fmt.Printf("  %s\n", file.Path)
+		}
+	}
+}
+
+func HandleUndoCommand(args []string) error {
+	count, err := parseCount(args)
+	if err != nil {
+		return err
+	}
+
+	var entries []logic.JournalEntry
+	err = logic.UpdateJournal(func(journal *logic.Journal) error {
+		entries, err = journal.Undo(count)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	printJournalEntries("Undone", entries)
+	return nil
+}
+
+func HandleRedoCommand(args []string) error {
+	count, err := parseCount(args)
+	if err != nil {
+		return err
+	}
+
+	var entries []logic.JournalEntry
+	err = logic.UpdateJournal(func(journal *logic.Journal) error {
+		entries, err = journal.Redo(count)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	printJournalEntries("Redone", entries)
+	return nil
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"yact/logic"
+)
+
+func HandleUnreadCommand(args []string, missing bool) error {
+	if len(args) == 0 && !missing {
+		fmt.Println("Usage: y unread <glob> [<glob2> ...] | y unread --missing")
+		return fmt.Errorf("missing pattern argument")
+	}
+
+	var removed []string
+	err := logic.UpdateContext(func(messages []logic.Message) ([]logic.Message, error) {
+		var kept []logic.Message
+		for _, message := range messages {
+			if message.Type == logic.MessageTypeFile && shouldUnread(message.Path, args, missing) {
+				removed = append(removed, message.Path)
+				continue
+			}
+			kept = append(kept, message)
+		}
+		return kept, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		fmt.Println("No matching files in context")
+		return nil
+	}
+
+	for _, path := range removed {
+		fmt.Printf("Removed: %s\n", path)
+	}
+	fmt.Printf("Removed %d file(s) from context\n", len(removed))
+	return nil
+}
+
+func shouldUnread(path string, patterns []string, missing bool) bool {
+	if missing {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return true
+		}
+	}
+
+	name := filepath.ToSlash(filepath.Clean(path))
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(filepath.Clean(pattern))
+		if name == pattern || strings.HasPrefix(name, pattern+"/") || logic.MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
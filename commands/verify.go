@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"yact/config"
+	"yact/logic"
+)
+
+const maxVerifyOutput = 16 * 1024
+
+func runVerifyCommands(ctx context.Context, commands []string) ([]logic.CommandResult, error) {
+	root, err := logic.FindProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []logic.CommandResult
+	for _, command := range commands {
+		fmt.Printf("Verifying: %s\n", command)
+		result, err := logic.RunShell(ctx, root, command)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Succeeded() {
+			fmt.Printf("Failed with exit code %d:\n%s\n", result.ExitCode, logic.TruncateOutput(result.Output, maxVerifyOutput))
+			failures = append(failures, result)
+		}
+	}
+	return failures, nil
+}
+
+func fixPrompt(failures []logic.CommandResult) string {
+	var prompt strings.Builder
+	prompt.WriteString("The changes failed verification. Fix the errors below. Make no other changes.\n")
+	for _, failure := range failures {
+		fmt.Fprintf(&prompt, "\n$ %s\n(exit code %d)\n%s\n", failure.Command, failure.ExitCode, logic.TruncateOutput(failure.Output, maxVerifyOutput))
+	}
+	return prompt.String()
+}
+
+func confirmProjectCommands(cfg *config.Config) bool {
+	if cfg.ProjectVerifyDir == "" || config.IsTrusted(cfg.ProjectVerifyDir, cfg.VerifyCommands) {
+		return true
+	}
+
+	fmt.Printf("%s defines verify commands:\n", filepath.Join(cfg.ProjectVerifyDir, "config"))
+	for _, command := range cfg.VerifyCommands {
+		fmt.Printf("  %s\n", command)
+	}
+
+	tty, err := openTerminal()
+	if err != nil {
+		fmt.Println("Skipping verification: project verify commands need to be confirmed in a terminal")
+		return false
+	}
+	if tty != os.Stdin {
+		defer tty.Close()
+	}
+
+	fmt.Print("Trust these commands and run them after writes in this project? [y/N] ")
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		fmt.Println("Skipping verification: project verify commands not trusted")
+		return false
+	}
+
+	if err := config.Trust(cfg.ProjectVerifyDir, cfg.VerifyCommands); err != nil {
+		fmt.Printf("Warning: could not remember trust: %v\n", err)
+	}
+	return true
+}
+
+func verifyWrites(ctx context.Context, written int, opts WriteOptions, cfg *config.Config, systemPrompt string) error {
+	if len(cfg.VerifyCommands) == 0 || written == 0 || opts.DryRun || opts.Safe {
+		return nil
+	}
+	if !confirmProjectCommands(cfg) {
+		return nil
+	}
+
+	for round := 0; ; round++ {
+		failures, err := runVerifyCommands(ctx, cfg.VerifyCommands)
+		if err != nil {
+			return err
+		}
+		if len(failures) == 0 {
+			fmt.Println("Verification passed")
+			return nil
+		}
+
+		if round >= opts.Fix {
+			if opts.Fix == 0 {
+				return fmt.Errorf("verification failed; use --fix N to let the model repair it")
+			}
+			return fmt.Errorf("verification still failing after %d repair round(s)", round)
+		}
+
+		fmt.Printf("Repair round %d of %d\n", round+1, opts.Fix)
+		responseContent, err := HandleCall(ctx, []string{fixPrompt(failures)}, cfg, systemPrompt, logic.MessageTypeCommand)
+		if err != nil {
+			return err
+		}
+
+		written, err = processCodeBlocks(ctx, responseContent, opts)
+		if err != nil {
+			return err
+		}
+		if written == 0 {
+			return fmt.Errorf("repair round %d wrote no files, stopping", round+1)
+		}
+	}
+}
+
+func writeAndVerify(ctx context.Context, responseContent string, opts WriteOptions, cfg *config.Config, systemPrompt string) (int, error) {
+	written, err := processCodeBlocks(ctx, responseContent, opts)
+	if err != nil {
+		return written, err
+	}
+	return written, verifyWrites(ctx, written, opts, cfg, systemPrompt)
+}
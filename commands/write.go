@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"yact/logic"
+)
+
+type WriteOptions struct {
+	Safe        bool
+	DryRun      bool
+	Interactive bool
+	Fix         int
+	Policy      *logic.PathPolicy
+}
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+	colorBold  = "\033[1m"
+)
+
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	stat, err := os.Stdout.Stat()
+	return err == nil && (stat.Mode()&os.ModeCharDevice) != 0
+}
+
+func colorizeDiff(diff string) string {
+	if !useColor() {
+		return diff
+	}
+
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = colorBold + line + colorReset
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = colorCyan + line + colorReset
+		case strings.HasPrefix(line, "+"):
+			lines[i] = colorGreen + line + colorReset
+		case strings.HasPrefix(line, "-"):
+			lines[i] = colorRed + line + colorReset
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func readCurrentContent(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+func codeBlockDiff(codeBlock logic.CodeBlock) (string, bool, error) {
+	current, exists, err := readCurrentContent(codeBlock.Path)
+	if err != nil {
+		return "", false, fmt.Errorf("error reading file %s: %w", codeBlock.Path, err)
+	}
+
+	oldName := "a/" + codeBlock.Path
+	if !exists {
+		oldName = "/dev/null"
+	}
+	return logic.UnifiedDiff(oldName, "b/"+codeBlock.Path, current, codeBlock.Content), exists, nil
+}
+
+func resolveCodeBlocks(content string, policy *logic.PathPolicy) ([]logic.CodeBlock, []string) {
+	var codeBlocks []logic.CodeBlock
+	var resolveErrors []string
+
+	for _, codeBlock := range logic.ParseCodeBlocks(content) {
+		if policy != nil {
+			if err := policy.Check(codeBlock.Path); err != nil {
+				resolveErrors = append(resolveErrors, fmt.Sprintf("rejected %s: %v", codeBlock.Path, err))
+				continue
+			}
+		}
+
+		resolved := codeBlock
+		if codeBlock.IsEdit() {
+			var failed []int
+			var err error
+			resolved, failed, err = codeBlock.ApplyEdits()
+			if err != nil {
+				resolveErrors = append(resolveErrors, fmt.Sprintf("%v", err))
+				continue
+			}
+
+			if len(failed) > 0 {
+				for _, hunk := range failed {
+					resolveErrors = append(resolveErrors, fmt.Sprintf("hunk %d did not match %s", hunk, codeBlock.Path))
+				}
+				resolveErrors = append(resolveErrors, fmt.Sprintf("skipped %s, no hunks were applied", codeBlock.Path))
+				continue
+			}
+		}
+
+		if err := checkRedactionMarkers(resolved); err != nil {
+			resolveErrors = append(resolveErrors, fmt.Sprintf("%v", err))
+			continue
+		}
+		codeBlocks = append(codeBlocks, resolved)
+	}
+
+	return codeBlocks, resolveErrors
+}
+
+func checkRedactionMarkers(codeBlock logic.CodeBlock) error {
+	current, _, err := readCurrentContent(codeBlock.Path)
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %w", codeBlock.Path, err)
+	}
+
+	if strings.Count(codeBlock.Content, logic.RedactionMarker) > strings.Count(current, logic.RedactionMarker) {
+		return fmt.Errorf("refusing to write %s: it contains redacted secret placeholders", codeBlock.Path)
+	}
+	return nil
+}
+
+func previewCodeBlocks(codeBlocks []logic.CodeBlock) []string {
+	var previewErrors []string
+	newFiles, modifiedFiles, unchangedFiles := 0, 0, 0
+
+	for _, codeBlock := range codeBlocks {
+		diff, exists, err := codeBlockDiff(codeBlock)
+		if err != nil {
+			previewErrors = append(previewErrors, fmt.Sprintf("%v", err))
+			continue
+		}
+
+		switch {
+		case !exists:
+			newFiles++
+		case diff == "":
+			unchangedFiles++
+			fmt.Printf("Unchanged: %s\n", codeBlock.Path)
+			continue
+		default:
+			modifiedFiles++
+		}
+		fmt.Print(colorizeDiff(diff))
+	}
+
+	fmt.Printf("Dry run: %d new, %d modified, %d unchanged. No files written.\n", newFiles, modifiedFiles, unchangedFiles)
+	return previewErrors
+}
+
+func processCodeBlocks(ctx context.Context, content string, opts WriteOptions) (int, error) {
+	if ctx.Err() != nil {
+		return 0, fmt.Errorf("cancelled before writing files")
+	}
+
+	fmt.Println("Processing response...")
+	codeBlocks, parseErrors := resolveCodeBlocks(content, opts.Policy)
+
+	batch := logic.NewWriteBatch(strings.Join(os.Args[1:], " "))
+	if opts.DryRun {
+		parseErrors = append(parseErrors, previewCodeBlocks(codeBlocks)...)
+	} else if opts.Interactive {
+		parseErrors = append(parseErrors, reviewCodeBlocks(ctx, batch, codeBlocks, opts)...)
+	} else {
+		for _, codeBlock := range codeBlocks {
+			if ctx.Err() != nil {
+				break
+			}
+			err := batch.Write(codeBlock, opts.Safe)
+			if err != nil {
+				parseErrors = append(parseErrors, fmt.Sprintf("%v", err))
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		written := batch.Len()
+		if err := batch.Rollback(); err != nil {
+			return 0, fmt.Errorf("cancelled, and could not roll back written files: %v", err)
+		}
+		return 0, fmt.Errorf("cancelled, restored %d written file(s)", written)
+	}
+
+	if err := batch.Commit(); err != nil {
+		fmt.Printf("Warning: could not record written files for undo: %v\n", err)
+	}
+
+	if len(parseErrors) > 0 {
+		return batch.Len(), fmt.Errorf("error processing code blocks: %s", strings.Join(parseErrors, "; "))
+	}
+
+	fmt.Println("Done!")
+	return batch.Len(), nil
+}
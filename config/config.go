@@ -2,19 +2,51 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 const (
-	ClaudeModel      = "claude-haiku-4-5-20251001"
-	DefaultMaxTokens = 8192
+	ClaudeModel        = "claude-haiku-4-5-20251001"
+	DefaultMaxTokens   = 8192
+	DefaultMaxRetries  = 3
+	DefaultKeepRecent  = 4
+	OpenAIBaseURL      = "https://api.openai.com/v1"
+	ProviderAnthropic  = "anthropic"
+	ProviderOpenAI     = "openai"
+	EditFormatWhole    = "whole"
+	EditFormatSearch   = "search-replace"
+	BudgetActionWarn   = "warn"
+	BudgetActionRefuse = "refuse"
+	RedactActionMask   = "mask"
+	RedactActionBlock  = "block"
+	RedactActionOff    = "off"
 )
 
 type Config struct {
-	AnthropicAPIKey string `json:"anthropic_api_key"`
-	ClaudeModel     string `json:"claude_model"`
-	MaxOutputTokens int    `json:"max_output_tokens"`
+	Provider          string   `json:"provider"`
+	AnthropicAPIKey   string   `json:"anthropic_api_key"`
+	ClaudeModel       string   `json:"claude_model"`
+	OpenAIAPIKey      string   `json:"openai_api_key"`
+	OpenAIModel       string   `json:"openai_model"`
+	BaseURL           string   `json:"base_url"`
+	MaxOutputTokens   int      `json:"max_output_tokens"`
+	MaxRetries        int      `json:"max_retries"`
+	EditFormat        string   `json:"edit_format"`
+	CompactAfter      int      `json:"compact_after"`
+	CompactKeep       int      `json:"compact_keep"`
+	TokenBudget       int      `json:"token_budget"`
+	TokenBudgetAction string   `json:"token_budget_action"`
+	AllowPaths        []string `json:"allow_paths,omitempty"`
+	DenyPaths         []string `json:"deny_paths,omitempty"`
+	RedactAction      string   `json:"redact_action"`
+	RedactPatterns    []string `json:"redact_patterns,omitempty"`
+	VerifyCommands    []string `json:"verify_commands,omitempty"`
+
+	ProjectVerifyDir string `json:"-"`
 }
 
 func getConfigDir() (string, error) {
@@ -33,15 +65,146 @@ func getConfigFile() (string, error) {
 	return filepath.Join(dir, "config"), nil
 }
 
+func FindProjectDir() (string, error) {
+	globalDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		candidate := filepath.Join(dir, ".yact")
+		if candidate != globalDir {
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				return candidate, nil
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			return "", nil
+		}
+	}
+}
+
+func DataDir() (string, error) {
+	projectDir, err := FindProjectDir()
+	if err != nil {
+		return "", err
+	}
+	if projectDir != "" {
+		return projectDir, nil
+	}
+	return getConfigDir()
+}
+
+func InitProjectDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(cwd, ".yact")
+	if _, err := os.Stat(dir); err == nil {
+		return dir, fmt.Errorf("%s already exists", dir)
+	}
+	return dir, os.MkdirAll(dir, 0755)
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return json.Unmarshal(data, cfg)
+}
+
+var projectKeys = map[string]bool{
+	"claude_model":        true,
+	"openai_model":        true,
+	"max_output_tokens":   true,
+	"max_retries":         true,
+	"edit_format":         true,
+	"compact_after":       true,
+	"compact_keep":        true,
+	"token_budget":        true,
+	"token_budget_action": true,
+	"allow_paths":         true,
+	"deny_paths":          true,
+	"redact_patterns":     true,
+	"verify_commands":     true,
+}
+
+func loadProjectFile(cfg *Config, projectDir string) error {
+	path := filepath.Join(projectDir, "config")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var ignored []string
+	for key := range raw {
+		if !projectKeys[key] {
+			ignored = append(ignored, key)
+			delete(raw, key)
+		}
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s in %s; set them with 'y config' instead\n", strings.Join(ignored, ", "), path)
+	}
+
+	globalDeny := append([]string{}, cfg.DenyPaths...)
+	globalRedact := append([]string{}, cfg.RedactPatterns...)
+	filtered, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(filtered, cfg); err != nil {
+		return err
+	}
+
+	if _, ok := raw["deny_paths"]; ok {
+		cfg.DenyPaths = append(globalDeny, cfg.DenyPaths...)
+	}
+	if _, ok := raw["redact_patterns"]; ok {
+		cfg.RedactPatterns = append(globalRedact, cfg.RedactPatterns...)
+	}
+	if _, ok := raw["verify_commands"]; ok {
+		cfg.ProjectVerifyDir = projectDir
+	}
+	return nil
+}
+
 func DefaultConfig() *Config {
 	return &Config{
-		AnthropicAPIKey: "",
-		ClaudeModel:     ClaudeModel,
-		MaxOutputTokens: DefaultMaxTokens,
+		Provider:          ProviderAnthropic,
+		AnthropicAPIKey:   "",
+		ClaudeModel:       ClaudeModel,
+		MaxOutputTokens:   DefaultMaxTokens,
+		MaxRetries:        DefaultMaxRetries,
+		EditFormat:        EditFormatWhole,
+		CompactKeep:       DefaultKeepRecent,
+		TokenBudgetAction: BudgetActionWarn,
+		RedactAction:      RedactActionMask,
 	}
 }
 
-func Load() (*Config, error) {
+func LoadGlobal() (*Config, error) {
 	cfg := DefaultConfig()
 
 	configFile, err := getConfigFile()
@@ -49,22 +212,45 @@ func Load() (*Config, error) {
 		return cfg, err
 	}
 
-	data, err := os.ReadFile(configFile)
+	if err := loadFile(cfg, configFile); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+func Load() (*Config, error) {
+	cfg, err := LoadGlobal()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return cfg, nil
-		}
 		return cfg, err
 	}
 
-	if err := json.Unmarshal(data, cfg); err != nil {
+	projectDir, err := FindProjectDir()
+	if err != nil {
 		return cfg, err
 	}
+	if projectDir != "" {
+		if err := loadProjectFile(cfg, projectDir); err != nil {
+			return cfg, fmt.Errorf("error reading project config: %w", err)
+		}
+	}
 
 	if cfg.MaxOutputTokens <= 0 {
 		cfg.MaxOutputTokens = DefaultMaxTokens
 	}
 
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+
+	if cfg.CompactKeep < 0 {
+		cfg.CompactKeep = DefaultKeepRecent
+	}
+
+	if cfg.RedactAction == "" {
+		cfg.RedactAction = RedactActionMask
+	}
+
 	return cfg, nil
 }
 
@@ -31,12 +31,16 @@ const Plan = "PLANNING AND ANALYSIS ASSISTANT\n\n" +
 	" - Include all functions and their signatures\n" +
 	" - Write in short, concise, declarative style\n" +
 	" - Make sure to refer to files with the exact path provided in the relevant code block\n" +
+	" - Number the steps as \"1. Title\", each step one change that can be implemented on its own\n" +
+	" - End each step with a line \"Files: path/one.ext, path/two.ext\" listing the files it creates or modifies\n" +
 	"EXAMPLE GOOD PLAN:\n" +
 	"Goal: Implement user authentication\n\n" +
 	"Components:\n" +
 	"1. Create Authentication handler (src/handlers/auth.go) with the folowing functions:\n" +
 	"   - LoginHandler()\n" +
-	"   - RegisterHandler()\n\n" +
+	"   - RegisterHandler()\n" +
+	"   Files: src/handlers/auth.go\n\n" +
 	"2. Create User model (src/models/user.go) with the following functions:\n" +
 	"   - User struct\n" +
-	"   - ValidatePassword() function\n\n"
+	"   - ValidatePassword() function\n" +
+	"   Files: src/models/user.go\n\n"
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func getTrustFile() (string, error) {
+	dir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trusted.json"), nil
+}
+
+func commandsHash(commands []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(commands, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func loadTrusted() (map[string]string, error) {
+	trusted := make(map[string]string)
+
+	trustFile, err := getTrustFile()
+	if err != nil {
+		return trusted, err
+	}
+
+	data, err := os.ReadFile(trustFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return trusted, nil
+		}
+		return trusted, err
+	}
+
+	return trusted, json.Unmarshal(data, &trusted)
+}
+
+func IsTrusted(projectDir string, commands []string) bool {
+	trusted, err := loadTrusted()
+	if err != nil {
+		return false
+	}
+	return trusted[projectDir] == commandsHash(commands)
+}
+
+func Trust(projectDir string, commands []string) error {
+	trusted, err := loadTrusted()
+	if err != nil {
+		return err
+	}
+	trusted[projectDir] = commandsHash(commands)
+
+	trustFile, err := getTrustFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(trustFile), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(trusted, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(trustFile, data, 0600)
+}
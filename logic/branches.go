@@ -0,0 +1,246 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const DefaultBranch = "main"
+
+type NodeKind string
+
+const (
+	NodeKindBranch     NodeKind = "branch"
+	NodeKindCheckpoint NodeKind = "checkpoint"
+)
+
+type ConversationNode struct {
+	Name     string
+	Kind     NodeKind
+	Parent   string
+	Created  time.Time
+	Messages []Message
+}
+
+type ConversationTree struct {
+	Current string
+	Nodes   []ConversationNode
+}
+
+func getTreeFilePathFor(session string) (string, error) {
+	dir, err := getYactDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "branches", session+".json"), nil
+}
+
+func getTreeFilePath() (string, error) {
+	session, err := ActiveSession()
+	if err != nil {
+		return "", err
+	}
+	return getTreeFilePathFor(session)
+}
+
+func loadTree() (*ConversationTree, error) {
+	treePath, err := getTreeFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &ConversationTree{}
+	data, err := os.ReadFile(treePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, tree); err != nil {
+			return nil, err
+		}
+	}
+
+	if tree.Current == "" {
+		tree.Current = DefaultBranch
+	}
+	if tree.Find(tree.Current) == nil {
+		tree.Nodes = append(tree.Nodes, ConversationNode{Name: tree.Current, Kind: NodeKindBranch, Created: time.Now()})
+	}
+	return tree, nil
+}
+
+func (t *ConversationTree) save() error {
+	treePath, err := getTreeFilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(treePath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(treePath, data)
+}
+
+func (t *ConversationTree) Find(name string) *ConversationNode {
+	for i := range t.Nodes {
+		if t.Nodes[i].Name == name {
+			return &t.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func (t *ConversationTree) Children(parent string) []ConversationNode {
+	var children []ConversationNode
+	for _, node := range t.Nodes {
+		if node.Parent == parent && node.Name != parent {
+			children = append(children, node)
+		}
+	}
+	return children
+}
+
+func (t *ConversationTree) uniqueName(base string) string {
+	for i := 1; ; i++ {
+		name := base + "-" + strconv.Itoa(i)
+		if t.Find(name) == nil {
+			return name
+		}
+	}
+}
+
+func withTree(update func(tree *ConversationTree, messages []Message) ([]Message, error)) error {
+	release, err := lockContext()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	tree, err := loadTree()
+	if err != nil {
+		return err
+	}
+
+	messages, err := LoadContext()
+	if err != nil {
+		return err
+	}
+
+	tree.Find(tree.Current).Messages = messages
+
+	updated, err := update(tree, messages)
+	if err != nil {
+		return err
+	}
+
+	if err := tree.save(); err != nil {
+		return err
+	}
+	return SaveContext(updated)
+}
+
+func validateNodeName(tree *ConversationTree, name string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if tree.Find(name) != nil {
+		return fmt.Errorf("'%s' already exists", name)
+	}
+	return nil
+}
+
+func CreateCheckpoint(name string) error {
+	return withTree(func(tree *ConversationTree, messages []Message) ([]Message, error) {
+		if err := validateNodeName(tree, name); err != nil {
+			return nil, err
+		}
+
+		tree.Nodes = append(tree.Nodes, ConversationNode{
+			Name:     name,
+			Kind:     NodeKindCheckpoint,
+			Parent:   tree.Current,
+			Created:  time.Now(),
+			Messages: append([]Message{}, messages...),
+		})
+		return messages, nil
+	})
+}
+
+func createBranch(tree *ConversationTree, name string, from string) ([]Message, error) {
+	source := tree.Find(from)
+	if source == nil {
+		return nil, fmt.Errorf("no branch or checkpoint named '%s'", from)
+	}
+
+	messages := append([]Message{}, source.Messages...)
+	tree.Nodes = append(tree.Nodes, ConversationNode{
+		Name:     name,
+		Kind:     NodeKindBranch,
+		Parent:   from,
+		Created:  time.Now(),
+		Messages: messages,
+	})
+	tree.Current = name
+	return messages, nil
+}
+
+func CreateBranch(name string, from string) error {
+	return withTree(func(tree *ConversationTree, messages []Message) ([]Message, error) {
+		if err := validateNodeName(tree, name); err != nil {
+			return nil, err
+		}
+		if from == "" {
+			from = tree.Current
+		}
+		return createBranch(tree, name, from)
+	})
+}
+
+func Checkout(name string) (string, error) {
+	var branch string
+	err := withTree(func(tree *ConversationTree, messages []Message) ([]Message, error) {
+		target := tree.Find(name)
+		if target == nil {
+			return nil, fmt.Errorf("no branch or checkpoint named '%s'", name)
+		}
+
+		if target.Kind == NodeKindCheckpoint {
+			branch = tree.uniqueName(name)
+			return createBranch(tree, branch, name)
+		}
+
+		branch = name
+		tree.Current = name
+		return append([]Message{}, target.Messages...), nil
+	})
+	return branch, err
+}
+
+func LoadConversationTree() (*ConversationTree, error) {
+	release, err := lockContext()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	tree, err := loadTree()
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := LoadContext()
+	if err != nil {
+		return nil, err
+	}
+	tree.Find(tree.Current).Messages = messages
+	return tree, nil
+}
@@ -0,0 +1,260 @@
+package logic
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxCollectedFileSize = 1 << 20
+	sniffSize            = 8000
+)
+
+var metadataDirNames = map[string]bool{
+	".git": true, ".hg": true, ".svn": true, ".yact": true,
+}
+
+var skippedDirNames = map[string]bool{
+	"node_modules": true, "vendor": true, "bower_components": true,
+	"__pycache__": true, ".venv": true, "venv": true, ".tox": true,
+	".mypy_cache": true, ".pytest_cache": true, ".gradle": true, ".next": true,
+	"dist": true, "build": true, "target": true,
+}
+
+var generatedFilePatterns = []string{
+	"*.min.js", "*.min.css", "*.map", "*.pb.go", "*_generated.go",
+	"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock", "go.sum", "*.lock",
+}
+
+type FileCollection struct {
+	Files     []string
+	Ignored   int
+	Binary    int
+	Generated int
+	TooLarge  int
+}
+
+func hasGlobMeta(segment string) bool {
+	return strings.ContainsAny(segment, "*?[")
+}
+
+func globBase(pattern string) string {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	var base []string
+	for _, segment := range segments[:len(segments)-1] {
+		if hasGlobMeta(segment) {
+			break
+		}
+		base = append(base, segment)
+	}
+	if len(base) == 0 {
+		return "."
+	}
+	if len(base) == 1 && base[0] == "" {
+		return "/"
+	}
+	return filepath.FromSlash(strings.Join(base, "/"))
+}
+
+func sniffFile(filePath string) (binary bool, generated bool, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, false, err
+	}
+	head = head[:n]
+
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true, false, nil
+	}
+	valid := head
+	for i := 0; i < utf8.UTFMax && len(valid) > 0 && !utf8.Valid(valid); i++ {
+		valid = valid[:len(valid)-1]
+	}
+	if !utf8.Valid(valid) {
+		return true, false, nil
+	}
+
+	firstLines := head
+	if len(firstLines) > 1024 {
+		firstLines = firstLines[:1024]
+	}
+	generated = bytes.Contains(firstLines, []byte("Code generated")) && bytes.Contains(firstLines, []byte("DO NOT EDIT"))
+	return false, generated, nil
+}
+
+func isGeneratedName(name string) bool {
+	for _, pattern := range generatedFilePatterns {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *FileCollection) addExplicit(filePath string) error {
+	binary, _, err := sniffFile(filePath)
+	if err != nil {
+		return err
+	}
+	if binary {
+		fmt.Printf("Skipping binary file: %s\n", filePath)
+		c.Binary++
+		return nil
+	}
+	c.Files = append(c.Files, filePath)
+	return nil
+}
+
+func (c *FileCollection) walk(root string, match func(string) bool, ignore *IgnoreMatcher) error {
+	return filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Printf("Error accessing %s: %v\n", filePath, err)
+			return nil
+		}
+
+		if entry.IsDir() {
+			if filePath == root {
+				return nil
+			}
+			if metadataDirNames[entry.Name()] {
+				return filepath.SkipDir
+			}
+			if skippedDirNames[entry.Name()] {
+				c.Generated++
+				return filepath.SkipDir
+			}
+			if ignore.Ignored(filePath, true) {
+				c.Ignored++
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || !match(filePath) {
+			return nil
+		}
+		if ignore.Ignored(filePath, false) {
+			c.Ignored++
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		c.addFound(filePath, info)
+		return nil
+	})
+}
+
+func (c *FileCollection) addFound(filePath string, info fs.FileInfo) {
+	if isGeneratedName(info.Name()) {
+		c.Generated++
+		return
+	}
+	if info.Size() > maxCollectedFileSize {
+		c.TooLarge++
+		return
+	}
+
+	binary, generated, err := sniffFile(filePath)
+	switch {
+	case err != nil:
+		fmt.Printf("Error reading %s: %v\n", filePath, err)
+	case binary:
+		c.Binary++
+	case generated:
+		c.Generated++
+	default:
+		c.Files = append(c.Files, filePath)
+	}
+}
+
+func CollectListedFiles(paths []string) *FileCollection {
+	collection := &FileCollection{}
+	for _, filePath := range paths {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			fmt.Printf("Error accessing %s: %v\n", filePath, err)
+			continue
+		}
+		collection.addFound(filePath, info)
+	}
+	return collection
+}
+
+func matchAll(string) bool {
+	return true
+}
+
+func CollectFiles(patterns []string) (*FileCollection, error) {
+	root, err := FindProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+	ignore := NewIgnoreMatcher(root)
+	collection := &FileCollection{}
+
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, "**") {
+			cleaned := filepath.Clean(pattern)
+			slashPattern := filepath.ToSlash(cleaned)
+			base := globBase(cleaned)
+			before := len(collection.Files)
+			err := collection.walk(base, func(filePath string) bool {
+				return MatchGlob(slashPattern, filepath.ToSlash(filePath))
+			}, ignore)
+			if err != nil {
+				return nil, err
+			}
+			if len(collection.Files) == before {
+				fmt.Printf("No files found matching pattern: %s\n", pattern)
+			}
+			continue
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error matching pattern %s: %w", pattern, err)
+		}
+
+		if len(matches) == 0 {
+			fmt.Printf("No files found matching pattern: %s\n", pattern)
+			continue
+		}
+
+		for _, filePath := range matches {
+			filePath = filepath.Clean(filePath)
+			info, err := os.Stat(filePath)
+			if err != nil {
+				fmt.Printf("Error accessing %s: %v\n", filePath, err)
+				continue
+			}
+
+			if info.IsDir() {
+				if err := collection.walk(filePath, matchAll, ignore); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if err := collection.addExplicit(filePath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return collection, nil
+}
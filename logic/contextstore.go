@@ -4,14 +4,19 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"yact/config"
 )
 
+func getYactDir() (string, error) {
+	return config.DataDir()
+}
+
 func getContextFilePath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	session, err := ActiveSession()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(homeDir, ".yact", "context.json"), nil
+	return getSessionFilePath(session)
 }
 func LoadContext() ([]Message, error) {
 	contextPath, err := getContextFilePath()
@@ -51,5 +56,5 @@ func SaveContext(messages []Message) error {
 		return err
 	}
 
-	return os.WriteFile(contextPath, data, 0644)
+	return writeFileAtomic(contextPath, data)
 }
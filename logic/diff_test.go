@@ -0,0 +1,130 @@
+package logic
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func numberedLines(numbers ...string) string {
+	return strings.Join(numbers, "\n") + "\n"
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "unchanged",
+			oldText: "a\nb\n",
+			newText: "a\nb\n",
+			want:    "",
+		},
+		{
+			name:    "new file",
+			oldText: "",
+			newText: "x\n",
+			want:    "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+x\n",
+		},
+		{
+			name:    "deleted content",
+			oldText: "x\n",
+			newText: "",
+			want:    "--- old\n+++ new\n@@ -1,1 +0,0 @@\n-x\n",
+		},
+		{
+			name:    "separate hunks",
+			oldText: numberedLines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15"),
+			newText: numberedLines("1", "2", "three", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "15", "16"),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+				"@@ -11,5 +11,5 @@\n 11\n 12\n 13\n-14\n 15\n+16\n",
+		},
+		{
+			name:    "nearby changes share a hunk",
+			oldText: numberedLines("1", "2", "3", "4", "5", "6", "7", "8"),
+			newText: numberedLines("one", "2", "3", "4", "5", "6", "7", "eight"),
+			want:    "--- old\n+++ new\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", test.oldText, test.newText); got != test.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func applyDiffOps(ops []diffOp) (string, string) {
+	var oldLines, newLines []string
+	for _, op := range ops {
+		if op.kind != '+' {
+			oldLines = append(oldLines, op.line)
+		}
+		if op.kind != '-' {
+			newLines = append(newLines, op.line)
+		}
+	}
+	return strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+}
+
+func TestDiffLinesReconstructsBothSides(t *testing.T) {
+	var base []string
+	for i := 0; i < 300; i++ {
+		base = append(base, fmt.Sprintf("line %d", i%37))
+	}
+
+	var changed []string
+	for i, line := range base {
+		switch i % 11 {
+		case 3:
+			continue
+		case 7:
+			changed = append(changed, "inserted", line)
+		case 9:
+			changed = append(changed, line+" changed")
+		default:
+			changed = append(changed, line)
+		}
+	}
+
+	for _, pair := range [][2][]string{
+		{base, changed},
+		{changed, base},
+		{nil, base},
+		{base, nil},
+		{base, base},
+	} {
+		oldText, newText := applyDiffOps(diffLines(pair[0], pair[1]))
+		if oldText != strings.Join(pair[0], "\n") || newText != strings.Join(pair[1], "\n") {
+			t.Errorf("diff of %d and %d lines does not reconstruct its inputs", len(pair[0]), len(pair[1]))
+		}
+	}
+}
+
+func TestDiffLinesLargeInputs(t *testing.T) {
+	var large []string
+	for i := 0; i < 20000; i++ {
+		large = append(large, fmt.Sprintf("line %d", i))
+	}
+	var rewritten []string
+	for i := range large {
+		rewritten = append(rewritten, fmt.Sprintf("other %d", i))
+	}
+
+	ops := diffLines(nil, large)
+	if len(ops) != len(large) || ops[0].kind != '+' {
+		t.Errorf("new file diff has %d ops, want %d additions", len(ops), len(large))
+	}
+
+	ops = diffLines(large, rewritten)
+	oldText, newText := applyDiffOps(ops)
+	if len(ops) != 2*len(large) || oldText != strings.Join(large, "\n") || newText != strings.Join(rewritten, "\n") {
+		t.Errorf("rewritten file diff has %d ops, want %d", len(ops), 2*len(large))
+	}
+}
@@ -0,0 +1,133 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	SearchMarker  = "<<<<<<< SEARCH"
+	DividerMarker = "======="
+	ReplaceMarker = ">>>>>>> REPLACE"
+)
+
+type Hunk struct {
+	Search  string
+	Replace string
+}
+
+func (cb *CodeBlock) IsEdit() bool {
+	for _, line := range strings.Split(cb.Content, "\n") {
+		if strings.TrimSpace(line) == SearchMarker {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseHunks(content string) ([]Hunk, error) {
+	var hunks []Hunk
+	var search, replace []string
+	state := 0
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == SearchMarker && state == 0:
+			search, replace = nil, nil
+			state = 1
+		case trimmed == DividerMarker && state == 1:
+			state = 2
+		case trimmed == ReplaceMarker && state == 2:
+			hunks = append(hunks, Hunk{Search: joinLines(search), Replace: joinLines(replace)})
+			state = 0
+		case state == 1:
+			search = append(search, line)
+		case state == 2:
+			replace = append(replace, line)
+		}
+	}
+
+	if state != 0 {
+		return hunks, fmt.Errorf("unterminated hunk %d", len(hunks)+1)
+	}
+	return hunks, nil
+}
+
+func ApplyHunks(original string, hunks []Hunk) (string, []int) {
+	result := original
+	var failed []int
+
+	for i, hunk := range hunks {
+		if hunk.Search == "" {
+			if strings.TrimSpace(result) != "" {
+				failed = append(failed, i+1)
+				continue
+			}
+			result = hunk.Replace + "\n"
+			continue
+		}
+
+		switch strings.Count(result, hunk.Search) {
+		case 1:
+			idx := strings.Index(result, hunk.Search)
+			result = result[:idx] + hunk.Replace + result[idx+len(hunk.Search):]
+			continue
+		case 0:
+			if replaced, ok := replaceIgnoringTrailingSpace(result, hunk); ok {
+				result = replaced
+				continue
+			}
+		}
+
+		failed = append(failed, i+1)
+	}
+
+	return result, failed
+}
+
+func replaceIgnoringTrailingSpace(content string, hunk Hunk) (string, bool) {
+	lines := strings.Split(content, "\n")
+	searchLines := strings.Split(hunk.Search, "\n")
+
+	match := -1
+	for start := 0; start+len(searchLines) <= len(lines); start++ {
+		matched := true
+		for j, searchLine := range searchLines {
+			if strings.TrimRight(lines[start+j], " \t\r") != strings.TrimRight(searchLine, " \t\r") {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			if match != -1 {
+				return "", false
+			}
+			match = start
+		}
+	}
+	if match == -1 {
+		return "", false
+	}
+
+	replaced := append([]string{}, lines[:match]...)
+	replaced = append(replaced, strings.Split(hunk.Replace, "\n")...)
+	replaced = append(replaced, lines[match+len(searchLines):]...)
+	return joinLines(replaced), true
+}
+
+func (cb *CodeBlock) ApplyEdits() (CodeBlock, []int, error) {
+	hunks, err := ParseHunks(cb.Content)
+	if err != nil {
+		return CodeBlock{}, nil, fmt.Errorf("error parsing hunks for %s: %w", cb.Path, err)
+	}
+
+	original, err := os.ReadFile(cb.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return CodeBlock{}, nil, fmt.Errorf("error reading file %s: %w", cb.Path, err)
+	}
+
+	content, failed := ApplyHunks(string(original), hunks)
+	return CodeBlock{Path: cb.Path, Content: content}, failed, nil
+}
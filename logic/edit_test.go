@@ -0,0 +1,119 @@
+package logic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Hunk
+		wantErr bool
+	}{
+		{
+			name:    "single hunk",
+			content: "<<<<<<< SEARCH\nold\n=======\nnew\n>>>>>>> REPLACE",
+			want:    []Hunk{{Search: "old", Replace: "new"}},
+		},
+		{
+			name:    "two hunks with surrounding text",
+			content: "intro\n<<<<<<< SEARCH\na\nb\n=======\nc\n>>>>>>> REPLACE\n<<<<<<< SEARCH\nd\n=======\n>>>>>>> REPLACE\n",
+			want:    []Hunk{{Search: "a\nb", Replace: "c"}, {Search: "d", Replace: ""}},
+		},
+		{
+			name:    "unterminated hunk",
+			content: "<<<<<<< SEARCH\nold\n=======\nnew",
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseHunks(test.content)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("hunks = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyHunks(t *testing.T) {
+	tests := []struct {
+		name       string
+		original   string
+		hunks      []Hunk
+		want       string
+		wantFailed []int
+	}{
+		{
+			name:     "exact match",
+			original: "a\nb\nc\n",
+			hunks:    []Hunk{{Search: "b", Replace: "B"}},
+			want:     "a\nB\nc\n",
+		},
+		{
+			name:     "match ignoring trailing whitespace",
+			original: "a  \nb\t\nc\n",
+			hunks:    []Hunk{{Search: "a\nb", Replace: "x"}},
+			want:     "x\nc\n",
+		},
+		{
+			name:       "no match",
+			original:   "a\nb\n",
+			hunks:      []Hunk{{Search: "z", Replace: "Z"}},
+			want:       "a\nb\n",
+			wantFailed: []int{1},
+		},
+		{
+			name:       "ambiguous match",
+			original:   "x := 1\ny := 2\nx := 1\n",
+			hunks:      []Hunk{{Search: "x := 1", Replace: "x := 3"}},
+			want:       "x := 1\ny := 2\nx := 1\n",
+			wantFailed: []int{1},
+		},
+		{
+			name:       "ambiguous match ignoring trailing whitespace",
+			original:   "a \nb\na\t\n",
+			hunks:      []Hunk{{Search: "a", Replace: "A"}, {Search: "b ", Replace: "B"}},
+			want:       "a \nB\na\t\n",
+			wantFailed: []int{1},
+		},
+		{
+			name:     "later hunk sees earlier replacement",
+			original: "one\ntwo\n",
+			hunks:    []Hunk{{Search: "one", Replace: "uno"}, {Search: "uno\ntwo", Replace: "done"}},
+			want:     "done\n",
+		},
+		{
+			name:     "empty search creates empty file",
+			original: "",
+			hunks:    []Hunk{{Search: "", Replace: "new"}},
+			want:     "new\n",
+		},
+		{
+			name:       "empty search on existing file",
+			original:   "content\n",
+			hunks:      []Hunk{{Search: "", Replace: "new"}},
+			want:       "content\n",
+			wantFailed: []int{1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, failed := ApplyHunks(test.original, test.hunks)
+			if got != test.want {
+				t.Errorf("result = %q, want %q", got, test.want)
+			}
+			if !reflect.DeepEqual(failed, test.wantFailed) {
+				t.Errorf("failed = %v, want %v", failed, test.wantFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package logic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const exportVersion = 1
+
+type Export struct {
+	Version  int
+	Session  string
+	Exported time.Time
+	Messages []Message
+}
+
+var knownMessageTypes = []MessageType{
+	MessageTypeFile,
+	MessageTypeQuestion,
+	MessageTypeAnswer,
+	MessageTypeCommand,
+	MessageTypeAction,
+	MessageTypeObjective,
+	MessageTypePlan,
+	MessageTypeRevision,
+	MessageTypeSummary,
+	MessageTypeOutput,
+}
+
+func IsKnownMessageType(messageType MessageType) bool {
+	for _, known := range knownMessageTypes {
+		if messageType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func ExportJSON(session string, messages []Message) ([]byte, error) {
+	return json.MarshalIndent(Export{
+		Version:  exportVersion,
+		Session:  session,
+		Exported: time.Now(),
+		Messages: messages,
+	}, "", "  ")
+}
+
+func RenderMarkdown(session string, messages []Message) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "# yact session: %s\n\n", session)
+	fmt.Fprintf(&out, "Exported %s, %d messages.\n", time.Now().Format("2006-01-02 15:04"), len(messages))
+
+	for i, message := range messages {
+		fmt.Fprintf(&out, "\n## [%d] %s", i, message.Type)
+		if message.Path != "" {
+			fmt.Fprintf(&out, " `%s`", message.Path)
+		}
+		out.WriteString("\n\n")
+
+		content := message.Content
+		if blocks := ParseCodeBlocks(content); message.Type == MessageTypeFile && len(blocks) == 1 {
+			content = blocks[0].Content
+		}
+		content = strings.TrimRight(content, "\n")
+		fence := MarkdownFence(content)
+		fmt.Fprintf(&out, "%s\n%s\n%s\n", fence, content, fence)
+	}
+
+	return out.String()
+}
+
+func MarkdownFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+func ParseExport(data []byte) ([]Message, error) {
+	var messages []Message
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("invalid session JSON: %w", err)
+		}
+	} else {
+		var export Export
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, fmt.Errorf("invalid session JSON: %w", err)
+		}
+		if export.Version > exportVersion {
+			return nil, fmt.Errorf("unsupported export version %d", export.Version)
+		}
+		messages = export.Messages
+	}
+
+	for i, message := range messages {
+		if !IsKnownMessageType(message.Type) {
+			return nil, fmt.Errorf("message %d has unknown type '%s'", i, message.Type)
+		}
+		if message.Type == MessageTypeFile && message.Path == "" {
+			return nil, fmt.Errorf("message %d is a File message without a path", i)
+		}
+		messages[i].Pending = false
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownFence(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"plain text", "```"},
+		{"inline `code` and ``double``", "```"},
+		{"```go\nfmt.Println()\n```", "````"},
+		{"````\n// main.go\n```\nnested\n```\n````", "`````"},
+	}
+
+	for _, test := range tests {
+		if got := MarkdownFence(test.content); got != test.want {
+			t.Errorf("MarkdownFence(%q) = %q, want %q", test.content, got, test.want)
+		}
+	}
+}
+
+func TestRenderMarkdownFencesEveryMessage(t *testing.T) {
+	messages := []Message{
+		{Type: MessageTypeFile, Path: "main.go", Content: AsCodeBlock("main.go", "package main\n")},
+		{Type: MessageTypeQuestion, Content: "# not a heading"},
+		{Type: MessageTypeAction, Content: "````\n// a.go\n```\n````"},
+	}
+
+	rendered := RenderMarkdown("default", messages)
+
+	for _, want := range []string{
+		"## [0] File `main.go`\n\n```\npackage main\n```\n",
+		"## [1] Question\n\n```\n# not a heading\n```\n",
+		"## [2] Action\n\n`````\n````\n// a.go\n```\n````\n`````\n",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("rendered markdown is missing %q:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestParseExport(t *testing.T) {
+	data, err := ExportJSON("default", []Message{
+		{Type: MessageTypeFile, Path: "a.go", Content: "a"},
+		{Type: MessageTypeCommand, Content: "do", Pending: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := ParseExport(data)
+	if err != nil {
+		t.Fatalf("ParseExport: %v", err)
+	}
+	if len(messages) != 2 || messages[0].Path != "a.go" || messages[1].Pending {
+		t.Errorf("ParseExport() = %+v", messages)
+	}
+
+	for _, invalid := range []string{
+		`[{"Type":"Unknown","Content":"x"}]`,
+		`[{"Type":"File","Content":"x"}]`,
+		`{"Version":99,"Messages":[]}`,
+		`not json`,
+	} {
+		if _, err := ParseExport([]byte(invalid)); err == nil {
+			t.Errorf("ParseExport(%s) succeeded, want an error", invalid)
+		}
+	}
+}
@@ -31,3 +31,39 @@ func ParseCodeBlocks(response string) []CodeBlock {
 
 	return codeBlocks
 }
+
+type CodeBlockTracker struct {
+	partial    string
+	inBlock    bool
+	expectPath bool
+}
+
+func (t *CodeBlockTracker) Feed(delta string) []string {
+	var paths []string
+	t.partial += delta
+	for {
+		idx := strings.Index(t.partial, "\n")
+		if idx < 0 {
+			break
+		}
+		line := t.partial[:idx]
+		t.partial = t.partial[idx+1:]
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, BlockDelimiter) {
+			t.inBlock = !t.inBlock
+			t.expectPath = t.inBlock
+			continue
+		}
+
+		if !t.inBlock || !t.expectPath || trimmed == "" || strings.HasPrefix(trimmed, "#!") {
+			continue
+		}
+
+		t.expectPath = false
+		if path := extractFilenameFromComment(line); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	result, err := RunCommand(ctx, dir, nil, "git", args...)
+	if err != nil {
+		return "", err
+	}
+	if !result.Succeeded() {
+		return "", fmt.Errorf("%s failed: %s", result.Command, strings.TrimSpace(result.Output))
+	}
+	return result.Output, nil
+}
+
+func GitRoot(ctx context.Context) (string, error) {
+	output, err := runGit(ctx, ".", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func gitDiffArgs(base string, staged bool, options ...string) []string {
+	args := append([]string{"diff", "--no-color", "--no-ext-diff"}, options...)
+	if staged {
+		args = append(args, "--cached")
+	}
+	return append(args, base, "--")
+}
+
+func GitDiff(ctx context.Context, base string, staged bool) (CommandResult, error) {
+	output, err := runGit(ctx, ".", gitDiffArgs(base, staged)...)
+	if err != nil {
+		return CommandResult{}, err
+	}
+
+	label := "git diff " + base
+	if staged {
+		label = "git diff --cached " + base
+	}
+	return CommandResult{Command: label, Output: output}, nil
+}
+
+func splitNul(output string) []string {
+	var items []string
+	for _, item := range strings.Split(output, "\x00") {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func GitChangedFiles(ctx context.Context, base string, staged bool) ([]string, error) {
+	root, err := GitRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runGit(ctx, root, gitDiffArgs(base, staged, "--name-only", "-z", "--diff-filter=d")...)
+	if err != nil {
+		return nil, err
+	}
+	names := splitNul(output)
+
+	if !staged {
+		untracked, err := runGit(ctx, root, "ls-files", "--others", "--exclude-standard", "-z")
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, splitNul(untracked)...)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range names {
+		absPath := filepath.Join(root, filepath.FromSlash(name))
+		if !fileExists(absPath) {
+			continue
+		}
+		if rel, err := filepath.Rel(cwd, absPath); err == nil {
+			absPath = rel
+		}
+		files = appendUnique(files, absPath)
+	}
+	return files, nil
+}
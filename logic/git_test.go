@@ -0,0 +1,98 @@
+package logic
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(root, "tracked.go"), "package main\n")
+	writeTestFile(t, filepath.Join(root, "removed.go"), "package main\n")
+	writeTestFile(t, filepath.Join(root, "sub", "staged.go"), "package sub\n")
+	writeTestFile(t, filepath.Join(root, ".gitignore"), "*.log\n")
+
+	runTestGit(t, root, "init", "-q")
+	runTestGit(t, root, "add", ".")
+	runTestGit(t, root, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial")
+	return root
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+func TestGitChangedFiles(t *testing.T) {
+	root := initTestRepo(t)
+	writeTestFile(t, filepath.Join(root, "tracked.go"), "package main\n\nfunc main() {}\n")
+	writeTestFile(t, filepath.Join(root, "sub", "staged.go"), "package sub\n\nvar x int\n")
+	writeTestFile(t, filepath.Join(root, "sub", "new file.go"), "package sub\n")
+	writeTestFile(t, filepath.Join(root, "debug.log"), "ignored\n")
+	runTestGit(t, root, "rm", "-q", "removed.go")
+	runTestGit(t, root, "add", "sub/staged.go")
+
+	tests := []struct {
+		name   string
+		dir    string
+		staged bool
+		want   []string
+	}{
+		{"working tree", root, false, []string{"sub/new file.go", "sub/staged.go", "tracked.go"}},
+		{"staged", root, true, []string{"sub/staged.go"}},
+		{"relative to a subdirectory", filepath.Join(root, "sub"), false, []string{"../tracked.go", "new file.go", "staged.go"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chdir(t, test.dir)
+
+			files, err := GitChangedFiles(context.Background(), "HEAD", test.staged)
+			if err != nil {
+				t.Fatalf("GitChangedFiles: %v", err)
+			}
+			for i, file := range files {
+				files[i] = filepath.ToSlash(file)
+			}
+			sort.Strings(files)
+			if !reflect.DeepEqual(files, test.want) {
+				t.Errorf("files = %q, want %q", files, test.want)
+			}
+		})
+	}
+}
+
+func TestGitDiff(t *testing.T) {
+	root := initTestRepo(t)
+	writeTestFile(t, filepath.Join(root, "tracked.go"), "package changed\n")
+	chdir(t, root)
+
+	result, err := GitDiff(context.Background(), "HEAD", false)
+	if err != nil {
+		t.Fatalf("GitDiff: %v", err)
+	}
+	if result.Command != "git diff HEAD" || !strings.Contains(result.Output, "+package changed") {
+		t.Errorf("GitDiff() = %+v", result)
+	}
+
+	if _, err := GitDiff(context.Background(), "no-such-ref", false); err == nil {
+		t.Error("GitDiff accepted an unknown ref")
+	}
+}
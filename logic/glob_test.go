@@ -0,0 +1,32 @@
+package logic
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "logic/edit.go", true},
+		{"*.go", "main.go.bak", false},
+		{"logic/*.go", "logic/edit.go", true},
+		{"logic/*.go", "logic/sub/edit.go", false},
+		{"logic/**", "logic/sub/edit.go", true},
+		{"logic/**", "logic", true},
+		{"**/.git/**", "vendor/.git/config", true},
+		{"**/.git/**", ".git/config", true},
+		{".git/**", "vendor/.git/config", false},
+		{"/docs/*.md", "docs/README.md", true},
+		{"a/**/z.txt", "a/b/c/z.txt", true},
+		{"a/**/z.txt", "a/z.txt", true},
+		{"a/**/z.txt", "b/a/z.txt", false},
+	}
+
+	for _, test := range tests {
+		if got := MatchGlob(test.pattern, test.name); got != test.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", test.pattern, test.name, got, test.want)
+		}
+	}
+}
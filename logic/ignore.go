@@ -0,0 +1,134 @@
+package logic
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var ignoreFileNames = []string{".gitignore", ".yactignore"}
+
+type ignoreRule struct {
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+type IgnoreMatcher struct {
+	root   string
+	loaded map[string]bool
+	rules  []ignoreRule
+}
+
+func NewIgnoreMatcher(root string) *IgnoreMatcher {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+	return &IgnoreMatcher{root: absRoot, loaded: make(map[string]bool)}
+}
+
+func (m *IgnoreMatcher) loadDir(dir string) {
+	if m.loaded[dir] {
+		return
+	}
+	m.loaded[dir] = true
+
+	for _, name := range ignoreFileNames {
+		file, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if rule, ok := parseIgnoreRule(dir, scanner.Text()); ok {
+				m.rules = append(m.rules, rule)
+			}
+		}
+		file.Close()
+	}
+}
+
+func parseIgnoreRule(base string, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, "\\")
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimLeft(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.pattern = line
+	return rule, true
+}
+
+func (m *IgnoreMatcher) loadAncestors(dir string) {
+	var dirs []string
+	for current := dir; ; current = filepath.Dir(current) {
+		dirs = append(dirs, current)
+		if current == m.root || filepath.Dir(current) == current {
+			break
+		}
+	}
+	if dirs[len(dirs)-1] != m.root {
+		dirs = dirs[:1]
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		m.loadDir(dirs[i])
+	}
+}
+
+func (r ignoreRule) matches(absPath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	rel, err := filepath.Rel(r.base, absPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	if r.anchored {
+		return matchSegments(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+	}
+	matched, _ := path.Match(r.pattern, path.Base(rel))
+	return matched
+}
+
+func (m *IgnoreMatcher) Ignored(filePath string, isDir bool) bool {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return false
+	}
+	m.loadAncestors(filepath.Dir(absPath))
+
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.matches(absPath, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,65 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, ".gitignore"), "# comment\n*.log\n!keep.log\nbuild/\n/vendor\ndocs/*.tmp\n")
+	writeTestFile(t, filepath.Join(root, ".yactignore"), "secrets.txt\n")
+	writeTestFile(t, filepath.Join(root, "sub", ".gitignore"), "local.txt\n!debug.log\n")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"main.go", false, false},
+		{"app.log", false, true},
+		{"sub/deep/app.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"sub/build", true, true},
+		{"vendor", true, true},
+		{"sub/vendor", true, false},
+		{"docs/notes.tmp", false, true},
+		{"docs/deep/notes.tmp", false, false},
+		{"secrets.txt", false, true},
+		{"sub/local.txt", false, true},
+		{"local.txt", false, false},
+		{"sub/debug.log", false, false},
+		{"other/debug.log", false, true},
+	}
+
+	matcher := NewIgnoreMatcher(root)
+	for _, test := range tests {
+		if got := matcher.Ignored(filepath.Join(root, test.path), test.isDir); got != test.want {
+			t.Errorf("Ignored(%q, dir=%v) = %v, want %v", test.path, test.isDir, got, test.want)
+		}
+	}
+}
+
+func TestIgnoreMatcherOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	other := t.TempDir()
+	writeTestFile(t, filepath.Join(root, ".gitignore"), "*.log\n")
+
+	matcher := NewIgnoreMatcher(root)
+	if matcher.Ignored(filepath.Join(other, "app.log"), false) {
+		t.Error("rules from the root were applied to a path outside it")
+	}
+}
@@ -0,0 +1,441 @@
+package logic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const maxJournalEntries = 50
+
+const blobSuffix = ".gz"
+
+type FileSnapshot struct {
+	Path       string
+	Existed    bool
+	BeforeHash string `json:",omitempty"`
+	AfterHash  string
+}
+
+type JournalEntry struct {
+	Time  time.Time
+	Label string
+	Files []FileSnapshot
+}
+
+type Journal struct {
+	Entries  []JournalEntry
+	Position int
+}
+
+type WriteBatch struct {
+	label    string
+	files    []FileSnapshot
+	index    map[string]int
+	contents map[string]string
+}
+
+type fileState struct {
+	exists bool
+	hash   string
+}
+
+func getJournalFilePath() (string, error) {
+	dir, err := getYactDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "journal.json"), nil
+}
+
+func getJournalBlobDir() (string, error) {
+	dir, err := getYactDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "journal"), nil
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeBlob(hash string, content string) error {
+	dir, err := getJournalBlobDir()
+	if err != nil {
+		return err
+	}
+	blobPath := filepath.Join(dir, hash+blobSuffix)
+	if _, err := os.Stat(blobPath); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return writeFileAtomic(blobPath, compressed.Bytes())
+}
+
+func readBlob(hash string) (string, error) {
+	dir, err := getJournalBlobDir()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(filepath.Join(dir, hash+blobSuffix))
+	if err != nil {
+		return "", fmt.Errorf("journal content %s is missing: %w", hash, err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	if contentHash(string(data)) != hash {
+		return "", fmt.Errorf("journal content %s is corrupted", hash)
+	}
+	return string(data), nil
+}
+
+func LoadJournal() (*Journal, error) {
+	journalPath, err := getJournalFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(journalPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Journal{}, nil
+		}
+		return nil, err
+	}
+
+	var journal Journal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, err
+	}
+	if journal.Position < 0 || journal.Position > len(journal.Entries) {
+		journal.Position = len(journal.Entries)
+	}
+	return &journal, nil
+}
+
+func (j *Journal) Save() error {
+	journalPath, err := getJournalFilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(journalPath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(journalPath, data); err != nil {
+		return err
+	}
+	return j.pruneBlobs()
+}
+
+func (j *Journal) pruneBlobs() error {
+	dir, err := getJournalBlobDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	referenced := make(map[string]bool)
+	for _, entry := range j.Entries {
+		for _, file := range entry.Files {
+			referenced[file.BeforeHash+blobSuffix] = true
+			referenced[file.AfterHash+blobSuffix] = true
+		}
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || !strings.HasSuffix(name, blobSuffix) || referenced[name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func UpdateJournal(update func(journal *Journal) error) error {
+	journalPath, err := getJournalFilePath()
+	if err != nil {
+		return err
+	}
+
+	release, err := acquireLock(journalPath + ".lock")
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	journal, err := LoadJournal()
+	if err != nil {
+		return fmt.Errorf("error loading journal: %w", err)
+	}
+
+	if err := update(journal); err != nil {
+		return err
+	}
+	return journal.Save()
+}
+
+func NewWriteBatch(label string) *WriteBatch {
+	return &WriteBatch{label: label, index: make(map[string]int), contents: make(map[string]string)}
+}
+
+func (b *WriteBatch) Write(cb CodeBlock, safe bool) error {
+	filePath := cb.Path
+	if safe {
+		filePath += ".new"
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return err
+	}
+
+	idx, seen := b.index[absPath]
+	if !seen {
+		snapshot := FileSnapshot{Path: absPath}
+		before, err := os.ReadFile(absPath)
+		if err == nil {
+			snapshot.Existed = true
+			snapshot.BeforeHash = contentHash(string(before))
+			b.contents[snapshot.BeforeHash] = string(before)
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("error reading file %s: %w", filePath, err)
+		}
+		b.files = append(b.files, snapshot)
+		idx = len(b.files) - 1
+	}
+
+	if err := cb.Write(safe); err != nil {
+		if !seen {
+			b.files = b.files[:idx]
+		}
+		return err
+	}
+
+	b.files[idx].AfterHash = contentHash(cb.Content)
+	b.contents[b.files[idx].AfterHash] = cb.Content
+	b.index[absPath] = idx
+	return nil
+}
+
+func (b *WriteBatch) Len() int {
+	return len(b.files)
+}
+
+func (b *WriteBatch) Rollback() error {
+	var rollbackErrors []string
+	for i := len(b.files) - 1; i >= 0; i-- {
+		file := b.files[i]
+		if err := restoreFile(file.Path, file.Existed, b.contents[file.BeforeHash]); err != nil {
+			rollbackErrors = append(rollbackErrors, fmt.Sprintf("error restoring %s: %v", file.Path, err))
+		}
+	}
+	b.files = nil
+	b.index = make(map[string]int)
+	b.contents = make(map[string]string)
+
+	if len(rollbackErrors) > 0 {
+		return fmt.Errorf("%s", strings.Join(rollbackErrors, "; "))
+	}
+	return nil
+}
+
+func (b *WriteBatch) Commit() error {
+	if len(b.files) == 0 {
+		return nil
+	}
+
+	return UpdateJournal(func(journal *Journal) error {
+		for hash, content := range b.contents {
+			if err := writeBlob(hash, content); err != nil {
+				return fmt.Errorf("error saving journal content: %w", err)
+			}
+		}
+
+		journal.Entries = append(journal.Entries[:journal.Position], JournalEntry{
+			Time:  time.Now(),
+			Label: b.label,
+			Files: b.files,
+		})
+		if len(journal.Entries) > maxJournalEntries {
+			journal.Entries = journal.Entries[len(journal.Entries)-maxJournalEntries:]
+		}
+		journal.Position = len(journal.Entries)
+		return nil
+	})
+}
+
+func currentState(path string) (fileState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fileState{}, nil
+		}
+		return fileState{}, err
+	}
+	return fileState{exists: true, hash: contentHash(string(data))}, nil
+}
+
+func restoreFile(path string, exists bool, content string) error {
+	if !exists {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, []byte(content))
+}
+
+func (f FileSnapshot) states(undo bool) (fileState, fileState) {
+	written := fileState{exists: true, hash: f.AfterHash}
+	original := fileState{exists: f.Existed, hash: f.BeforeHash}
+	if undo {
+		return written, original
+	}
+	return original, written
+}
+
+func checkEntries(entries []JournalEntry, undo bool) error {
+	expected := make(map[string]fileState)
+	for _, entry := range entries {
+		for _, file := range entry.Files {
+			state, seen := expected[file.Path]
+			if !seen {
+				var err error
+				if state, err = currentState(file.Path); err != nil {
+					return err
+				}
+			}
+
+			from, to := file.states(undo)
+			if state != from {
+				return fmt.Errorf("%s was modified since it was written", file.Path)
+			}
+			expected[file.Path] = to
+		}
+	}
+	return nil
+}
+
+func (e *JournalEntry) apply(undo bool) error {
+	for i := len(e.Files) - 1; i >= 0; i-- {
+		file := e.Files[i]
+		_, to := file.states(undo)
+
+		content := ""
+		if to.exists {
+			var err error
+			if content, err = readBlob(to.hash); err != nil {
+				return fmt.Errorf("error restoring %s: %w", file.Path, err)
+			}
+		}
+		if err := restoreFile(file.Path, to.exists, content); err != nil {
+			return fmt.Errorf("error restoring %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
+
+func applyEntries(entries []JournalEntry, undo bool) error {
+	for i := range entries {
+		if err := entries[i].apply(undo); err != nil {
+			for k := i; k >= 0; k-- {
+				if restoreErr := entries[k].apply(!undo); restoreErr != nil {
+					return fmt.Errorf("%v (and could not put files back: %v)", err, restoreErr)
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *Journal) Undo(count int) ([]JournalEntry, error) {
+	if count > j.Position {
+		return nil, fmt.Errorf("only %d write(s) can be undone", j.Position)
+	}
+
+	entries := make([]JournalEntry, 0, count)
+	for i := j.Position - 1; i >= j.Position-count; i-- {
+		entries = append(entries, j.Entries[i])
+	}
+
+	if err := checkEntries(entries, true); err != nil {
+		return nil, fmt.Errorf("refusing to undo: %w", err)
+	}
+	if err := applyEntries(entries, true); err != nil {
+		return nil, err
+	}
+
+	j.Position -= count
+	return entries, nil
+}
+
+func (j *Journal) Redo(count int) ([]JournalEntry, error) {
+	if j.Position+count > len(j.Entries) {
+		return nil, fmt.Errorf("only %d write(s) can be redone", len(j.Entries)-j.Position)
+	}
+
+	entries := j.Entries[j.Position : j.Position+count]
+	if err := checkEntries(entries, false); err != nil {
+		return nil, fmt.Errorf("refusing to redo: %w", err)
+	}
+	if err := applyEntries(entries, false); err != nil {
+		return nil, err
+	}
+
+	j.Position += count
+	return entries, nil
+}
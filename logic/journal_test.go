@@ -0,0 +1,206 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readTestFile(t *testing.T, path string) (string, bool) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), true
+}
+
+func TestWriteBatchRollback(t *testing.T) {
+	root := t.TempDir()
+	chdir(t, root)
+	writeTestFile(t, filepath.Join(root, "existing.go"), "before\n")
+
+	batch := NewWriteBatch("test")
+	for _, cb := range []CodeBlock{
+		{Path: "existing.go", Content: "after\n"},
+		{Path: "new/file.go", Content: "created\n"},
+		{Path: "existing.go", Content: "after again\n"},
+	} {
+		if err := batch.Write(cb, false); err != nil {
+			t.Fatalf("Write(%s): %v", cb.Path, err)
+		}
+	}
+	if batch.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", batch.Len())
+	}
+
+	if err := batch.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if content, _ := readTestFile(t, "existing.go"); content != "before\n" {
+		t.Errorf("existing.go = %q, want the original content", content)
+	}
+	if _, exists := readTestFile(t, "new/file.go"); exists {
+		t.Error("new/file.go still exists after rollback")
+	}
+	if batch.Len() != 0 {
+		t.Errorf("Len() after rollback = %d, want 0", batch.Len())
+	}
+}
+
+func setupYactDir(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".yact"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, root)
+	return root
+}
+
+func commitTestBatch(t *testing.T, label string, blocks ...CodeBlock) {
+	t.Helper()
+	batch := NewWriteBatch(label)
+	for _, cb := range blocks {
+		if err := batch.Write(cb, false); err != nil {
+			t.Fatalf("Write(%s): %v", cb.Path, err)
+		}
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+func undoRedo(undo bool, count int) error {
+	return UpdateJournal(func(journal *Journal) error {
+		var err error
+		if undo {
+			_, err = journal.Undo(count)
+		} else {
+			_, err = journal.Redo(count)
+		}
+		return err
+	})
+}
+
+func expectFile(t *testing.T, path string, want string, wantExists bool) {
+	t.Helper()
+	content, exists := readTestFile(t, path)
+	if exists != wantExists || content != want {
+		t.Errorf("%s = %q (exists %v), want %q (exists %v)", path, content, exists, want, wantExists)
+	}
+}
+
+func journalPosition(t *testing.T) int {
+	t.Helper()
+	journal, err := LoadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return journal.Position
+}
+
+func TestJournalUndoRedo(t *testing.T) {
+	root := setupYactDir(t)
+	writeTestFile(t, filepath.Join(root, "a.go"), "v1\n")
+
+	commitTestBatch(t, "first", CodeBlock{Path: "a.go", Content: "v2\n"}, CodeBlock{Path: "b.go", Content: "new\n"})
+	commitTestBatch(t, "second", CodeBlock{Path: "a.go", Content: "v3\n"})
+
+	if err := undoRedo(true, 2); err != nil {
+		t.Fatalf("undo 2: %v", err)
+	}
+	expectFile(t, "a.go", "v1\n", true)
+	expectFile(t, "b.go", "", false)
+	if pos := journalPosition(t); pos != 0 {
+		t.Errorf("position after undo = %d, want 0", pos)
+	}
+
+	if err := undoRedo(true, 1); err == nil {
+		t.Error("undo beyond the first entry succeeded")
+	}
+
+	if err := undoRedo(false, 1); err != nil {
+		t.Fatalf("redo 1: %v", err)
+	}
+	expectFile(t, "a.go", "v2\n", true)
+	expectFile(t, "b.go", "new\n", true)
+
+	if err := undoRedo(false, 1); err != nil {
+		t.Fatalf("redo 1: %v", err)
+	}
+	expectFile(t, "a.go", "v3\n", true)
+	if pos := journalPosition(t); pos != 2 {
+		t.Errorf("position after redo = %d, want 2", pos)
+	}
+}
+
+func TestJournalUndoChecksEveryEntryFirst(t *testing.T) {
+	root := setupYactDir(t)
+
+	commitTestBatch(t, "first", CodeBlock{Path: "a.go", Content: "a1\n"})
+	commitTestBatch(t, "second", CodeBlock{Path: "b.go", Content: "b1\n"})
+	writeTestFile(t, filepath.Join(root, "a.go"), "edited by hand\n")
+
+	if err := undoRedo(true, 2); err == nil {
+		t.Fatal("undo succeeded although a.go was modified")
+	}
+	expectFile(t, "b.go", "b1\n", true)
+	expectFile(t, "a.go", "edited by hand\n", true)
+	if pos := journalPosition(t); pos != 2 {
+		t.Errorf("position after refused undo = %d, want 2", pos)
+	}
+}
+
+func TestJournalStoresDeduplicatedContent(t *testing.T) {
+	root := setupYactDir(t)
+
+	commitTestBatch(t, "first", CodeBlock{Path: "a.go", Content: "v1\n"})
+	commitTestBatch(t, "second", CodeBlock{Path: "a.go", Content: "v2\n"})
+	commitTestBatch(t, "third", CodeBlock{Path: "a.go", Content: "v1\n"})
+
+	blobs, err := os.ReadDir(filepath.Join(root, ".yact", "journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 2 {
+		t.Errorf("journal holds %d content blobs, want 2", len(blobs))
+	}
+
+	if err := undoRedo(true, 2); err != nil {
+		t.Fatalf("undo 2: %v", err)
+	}
+	commitTestBatch(t, "replacement", CodeBlock{Path: "a.go", Content: "v4\n"})
+
+	blobs, err = os.ReadDir(filepath.Join(root, ".yact", "journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 2 {
+		t.Errorf("journal holds %d content blobs after dropping redo entries, want 2", len(blobs))
+	}
+}
+
+func TestJournalUndoPutsFilesBackOnFailure(t *testing.T) {
+	root := setupYactDir(t)
+	writeTestFile(t, filepath.Join(root, "a.go"), "a0\n")
+
+	commitTestBatch(t, "first", CodeBlock{Path: "a.go", Content: "a1\n"})
+	commitTestBatch(t, "second", CodeBlock{Path: "b.go", Content: "b1\n"})
+
+	if err := os.Remove(filepath.Join(root, ".yact", "journal", contentHash("a0\n")+blobSuffix)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := undoRedo(true, 2); err == nil {
+		t.Fatal("undo succeeded although saved content was missing")
+	}
+	expectFile(t, "a.go", "a1\n", true)
+	expectFile(t, "b.go", "b1\n", true)
+	if pos := journalPosition(t); pos != 2 {
+		t.Errorf("position after failed undo = %d, want 2", pos)
+	}
+}
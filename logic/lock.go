@@ -0,0 +1,62 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockTimeout      = 10 * time.Second
+	lockPollInterval = 50 * time.Millisecond
+)
+
+func acquireLock(lockPath string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		release, acquired, err := tryLock(lockPath)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return release, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s (is another y command running?)", lockPath)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+func lockContext() (func(), error) {
+	contextPath, err := getContextFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return acquireLock(contextPath + ".lock")
+}
+
+func UpdateContext(update func(messages []Message) ([]Message, error)) error {
+	release, err := lockContext()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	messages, err := LoadContext()
+	if err != nil {
+		return err
+	}
+
+	updated, err := update(messages)
+	if err != nil {
+		return err
+	}
+
+	return SaveContext(updated)
+}
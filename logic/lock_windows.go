@@ -0,0 +1,29 @@
+//go:build windows
+
+package logic
+
+import (
+	"os"
+	"time"
+)
+
+const staleLockAge = 2 * time.Minute
+
+func tryLock(lockPath string) (func(), bool, error) {
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if !os.IsExist(err) {
+			return nil, false, err
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+		}
+		return nil, false, nil
+	}
+	file.Close()
+
+	release := func() {
+		os.Remove(lockPath)
+	}
+	return release, true, nil
+}
@@ -6,34 +6,43 @@ func LoadContextForMessageType(messageType MessageType) ([]Message, error) {
 		return nil, err
 	}
 
-	var allowedTypes []MessageType
+	return FilterForMessageType(messages, messageType), nil
+}
 
+func allowedTypes(messageType MessageType) []MessageType {
 	switch messageType {
 	case MessageTypeCommand:
-		allowedTypes = []MessageType{MessageTypeFile, MessageTypeCommand, MessageTypeAction, MessageTypePlan}
+		return []MessageType{MessageTypeFile, MessageTypeSummary, MessageTypeOutput, MessageTypeCommand, MessageTypeAction, MessageTypePlan}
 	case MessageTypeObjective:
-		allowedTypes = []MessageType{MessageTypeFile, MessageTypeQuestion, MessageTypeAnswer, MessageTypeObjective, MessageTypePlan, MessageTypeRevision}
+		return []MessageType{MessageTypeFile, MessageTypeSummary, MessageTypeOutput, MessageTypeQuestion, MessageTypeAnswer, MessageTypeObjective, MessageTypePlan, MessageTypeRevision}
 	case MessageTypeQuestion:
-		allowedTypes = []MessageType{MessageTypeFile, MessageTypeQuestion, MessageTypeAnswer, MessageTypeObjective, MessageTypePlan}
-	default:
-		return make([]Message, 0), nil
+		return []MessageType{MessageTypeFile, MessageTypeSummary, MessageTypeOutput, MessageTypeQuestion, MessageTypeAnswer, MessageTypeObjective, MessageTypePlan}
 	}
+	return nil
+}
 
-	var filtered []Message
-	for _, msg := range messages {
-		for _, allowed := range allowedTypes {
-			if msg.Type == allowed {
-				if messageType == MessageTypeObjective && msg.Type == MessageTypePlan {
-					filtered = append(filtered, Message{Type: MessageTypeRevision, Content: msg.Content})
-				} else {
-					filtered = append(filtered, msg)
-				}
-				break
-			}
+func SentForMessageType(message Message, messageType MessageType) bool {
+	for _, allowed := range allowedTypes(messageType) {
+		if message.Type == allowed {
+			return true
 		}
 	}
+	return false
+}
 
-	return filtered, nil
+func FilterForMessageType(messages []Message, messageType MessageType) []Message {
+	filtered := make([]Message, 0)
+	for _, msg := range messages {
+		if !SentForMessageType(msg, messageType) {
+			continue
+		}
+		if messageType == MessageTypeObjective && msg.Type == MessageTypePlan {
+			filtered = append(filtered, Message{Type: MessageTypeRevision, Content: msg.Content})
+		} else {
+			filtered = append(filtered, msg)
+		}
+	}
+	return filtered
 }
 
 type MessageType string
@@ -47,6 +56,8 @@ const (
 	MessageTypeObjective MessageType = "Objective"
 	MessageTypePlan      MessageType = "Plan"
 	MessageTypeRevision  MessageType = "Revision"
+	MessageTypeSummary   MessageType = "Summary"
+	MessageTypeOutput    MessageType = "CommandOutput"
 )
 
 func ResponseType(messageType MessageType) MessageType {
@@ -67,4 +78,7 @@ type Message struct {
 	Type    MessageType
 	Path    string
 	Content string
+	Pending bool       `json:",omitempty"`
+	Mode    string     `json:",omitempty"`
+	Steps   []PlanStep `json:",omitempty"`
 }
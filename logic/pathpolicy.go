@@ -0,0 +1,148 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var defaultDenyPatterns = []string{".git/**", "**/.git/**", ".yact/**", "**/.yact/**"}
+
+var sensitiveHomeDirs = []string{".ssh", ".gnupg", ".aws", ".kube", ".docker", ".config/gcloud", ".yact"}
+
+type PathPolicy struct {
+	Root  string
+	Allow []string
+	Deny  []string
+}
+
+func FindProjectRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, nil
+		}
+		if filepath.Dir(dir) == dir {
+			return cwd, nil
+		}
+	}
+}
+
+func NewPathPolicy(allow []string, deny []string) (*PathPolicy, error) {
+	root, err := FindProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PathPolicy{
+		Root:  root,
+		Allow: allow,
+		Deny:  append(append([]string{}, defaultDenyPatterns...), deny...),
+	}, nil
+}
+
+func resolveExisting(path string) (string, error) {
+	missing := []string{}
+	current := path
+	for {
+		resolved, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			for i := len(missing) - 1; i >= 0; i-- {
+				resolved = filepath.Join(resolved, missing[i])
+			}
+			return resolved, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return path, nil
+		}
+		missing = append(missing, filepath.Base(current))
+		current = parent
+	}
+}
+
+func isWithin(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (p *PathPolicy) Check(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	if !isWithin(p.Root, absPath) {
+		return fmt.Errorf("%s is outside the project root %s", path, p.Root)
+	}
+
+	resolvedRoot, err := resolveExisting(p.Root)
+	if err != nil {
+		return err
+	}
+	resolvedPath, err := resolveExisting(absPath)
+	if err != nil {
+		return err
+	}
+	if !isWithin(resolvedRoot, resolvedPath) {
+		return fmt.Errorf("%s resolves through a symlink to %s, outside the project root", path, resolvedPath)
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		for _, dir := range sensitiveHomeDirs {
+			sensitive := filepath.Join(homeDir, dir)
+			if isWithin(sensitive, absPath) || isWithin(sensitive, resolvedPath) {
+				return fmt.Errorf("%s is inside the protected directory %s", path, sensitive)
+			}
+		}
+	}
+
+	rel, err := filepath.Rel(p.Root, absPath)
+	if err != nil {
+		return err
+	}
+	resolvedRel, err := filepath.Rel(resolvedRoot, resolvedPath)
+	if err != nil {
+		return err
+	}
+	rels := []string{filepath.ToSlash(rel), filepath.ToSlash(resolvedRel)}
+
+	for _, pattern := range p.Deny {
+		for _, candidate := range rels {
+			if MatchGlob(pattern, candidate) {
+				return fmt.Errorf("%s matches denied pattern %s", path, pattern)
+			}
+		}
+	}
+
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, candidate := range rels {
+		if !matchesAny(p.Allow, candidate) {
+			return fmt.Errorf("%s does not match any allowed pattern", path)
+		}
+	}
+	return nil
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
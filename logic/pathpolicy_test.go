@@ -0,0 +1,109 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+}
+
+func TestPathPolicyCheck(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "src"), filepath.Join(root, "inside")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".git", "hooks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(".git", "hooks"), filepath.Join(root, "hooks")); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, root)
+
+	policy := &PathPolicy{
+		Root: root,
+		Deny: append(append([]string{}, defaultDenyPatterns...), "*.pem"),
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		allowed bool
+	}{
+		{"relative file", "src/main.go", true},
+		{"new nested directory", "src/new/dir/file.go", true},
+		{"dot segments staying inside", "src/../main.go", true},
+		{"parent directory", "../file.go", false},
+		{"dot segments escaping", "src/../../file.go", false},
+		{"absolute path inside", filepath.Join(root, "src", "main.go"), true},
+		{"absolute path outside", filepath.Join(outside, "file.go"), false},
+		{"symlink escaping the root", "escape/file.go", false},
+		{"symlink inside the root", "inside/file.go", true},
+		{"git directory", ".git/config", false},
+		{"nested git directory", "vendor/lib/.git/HEAD", false},
+		{"symlink into the git directory", "hooks/pre-commit", false},
+		{"yact directory", ".yact/config", false},
+		{"configured deny pattern", "certs/server.pem", false},
+		{"file named like git", ".gitignore", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := policy.Check(test.path)
+			if test.allowed && err != nil {
+				t.Errorf("Check(%q) = %v, want allowed", test.path, err)
+			}
+			if !test.allowed && err == nil {
+				t.Errorf("Check(%q) allowed, want rejected", test.path)
+			}
+		})
+	}
+}
+
+func TestPathPolicyAllow(t *testing.T) {
+	root := t.TempDir()
+	chdir(t, root)
+
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "scripts"), filepath.Join(root, "src", "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	policy := &PathPolicy{Root: root, Allow: []string{"src/**", "*.md"}, Deny: defaultDenyPatterns}
+
+	for path, allowed := range map[string]bool{
+		"src/a/b.go":     true,
+		"README.md":      true,
+		"docs/guide.md":  true,
+		"main.go":        false,
+		"scripts/run.sh": false,
+		"src/link/a.go":  false,
+	} {
+		if err := policy.Check(path); (err == nil) != allowed {
+			t.Errorf("Check(%q) = %v, want allowed %v", path, err, allowed)
+		}
+	}
+}
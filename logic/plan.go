@@ -0,0 +1,128 @@
+package logic
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type PlanStep struct {
+	Number      int
+	Title       string
+	Description string
+	Files       []string `json:",omitempty"`
+	Done        bool     `json:",omitempty"`
+}
+
+var (
+	stepHeading   = regexp.MustCompile(`^ ?(?:#{1,6}\s*)?(?:\*\*)?(?:[Ss]tep\s+)?(\d+)[.):]\s*(?:\*\*)?\s*(.*)$`)
+	filesLine     = regexp.MustCompile(`^\s*(?:[-*]\s*)?(?:\*\*)?[Ff]iles?:(?:\*\*)?\s*(.*)$`)
+	pathCandidate = regexp.MustCompile("[\\w.-]*(?:/[\\w.-]+)*\\.[A-Za-z][A-Za-z0-9]{0,9}")
+)
+
+func ParsePlanSteps(content string) []PlanStep {
+	var steps []PlanStep
+	var lines []string
+
+	flush := func() {
+		if len(steps) == 0 {
+			return
+		}
+		step := &steps[len(steps)-1]
+		step.Description = strings.TrimSpace(strings.Join(lines, "\n"))
+		step.Files = stepFiles(lines)
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if match := stepHeading.FindStringSubmatch(line); match != nil {
+			number, _ := strconv.Atoi(match[1])
+			if number == len(steps)+1 {
+				flush()
+				steps = append(steps, PlanStep{Number: number, Title: strings.TrimSpace(strings.TrimSuffix(match[2], "**"))})
+				lines = []string{line}
+				continue
+			}
+		}
+		if len(steps) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+
+	return steps
+}
+
+func stepFiles(lines []string) []string {
+	var listed []string
+	for _, line := range lines {
+		if match := filesLine.FindStringSubmatch(line); match != nil {
+			for _, item := range strings.FieldsFunc(match[1], func(r rune) bool { return r == ',' || r == ' ' }) {
+				if item = strings.Trim(item, "`'\"()*"); item != "" {
+					listed = appendUnique(listed, item)
+				}
+			}
+		}
+	}
+	if len(listed) > 0 {
+		return listed
+	}
+
+	var found []string
+	for _, line := range lines {
+		for _, candidate := range pathCandidate.FindAllString(line, -1) {
+			candidate = strings.Trim(candidate, ".")
+			if strings.Contains(candidate, "/") || fileExists(candidate) {
+				found = appendUnique(found, candidate)
+			}
+		}
+	}
+	return found
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func appendUnique(items []string, item string) []string {
+	for _, existing := range items {
+		if existing == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+func LatestPlanIndex(messages []Message) int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if len(messages[i].Steps) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func FindPlanStep(steps []PlanStep, number int) int {
+	for i, step := range steps {
+		if step.Number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+func MarkStepDone(number int, planContent string) error {
+	return UpdateContext(func(messages []Message) ([]Message, error) {
+		for i := len(messages) - 1; i >= 0; i-- {
+			if len(messages[i].Steps) == 0 || messages[i].Content != planContent {
+				continue
+			}
+			if idx := FindPlanStep(messages[i].Steps, number); idx != -1 {
+				messages[i].Steps[idx].Done = true
+			}
+			return messages, nil
+		}
+		return messages, nil
+	})
+}
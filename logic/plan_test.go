@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePlanSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []PlanStep
+	}{
+		{
+			name:    "no steps",
+			content: "Just some prose.\n- a bullet",
+			want:    nil,
+		},
+		{
+			name:    "numbered list with files lines",
+			content: "Plan:\n1. Add parser\n   Files: logic/parse.go, logic/parse_test.go\n2. Wire command\n   - **Files:** `main.go`\n",
+			want: []PlanStep{
+				{Number: 1, Title: "Add parser", Description: "1. Add parser\n   Files: logic/parse.go, logic/parse_test.go", Files: []string{"logic/parse.go", "logic/parse_test.go"}},
+				{Number: 2, Title: "Wire command", Description: "2. Wire command\n   - **Files:** `main.go`", Files: []string{"main.go"}},
+			},
+		},
+		{
+			name:    "markdown headings",
+			content: "## Step 1: Refactor\nMove code into commands/run.go.\n### **Step 2) Document**\nUpdate docs/usage.md and docs/usage.md again.",
+			want: []PlanStep{
+				{Number: 1, Title: "Refactor", Description: "## Step 1: Refactor\nMove code into commands/run.go.", Files: []string{"commands/run.go"}},
+				{Number: 2, Title: "Document", Description: "### **Step 2) Document**\nUpdate docs/usage.md and docs/usage.md again.", Files: []string{"docs/usage.md"}},
+			},
+		},
+		{
+			name:    "out of sequence numbers are part of the description",
+			content: "1. First\n3. not a step\n2. Second",
+			want: []PlanStep{
+				{Number: 1, Title: "First", Description: "1. First\n3. not a step"},
+				{Number: 2, Title: "Second", Description: "2. Second"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ParsePlanSteps(test.content)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParsePlanSteps() =\n%#v\nwant\n%#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFindPlanStep(t *testing.T) {
+	steps := []PlanStep{{Number: 1}, {Number: 2}}
+	if got := FindPlanStep(steps, 2); got != 1 {
+		t.Errorf("FindPlanStep(2) = %d, want 1", got)
+	}
+	if got := FindPlanStep(steps, 3); got != -1 {
+		t.Errorf("FindPlanStep(3) = %d, want -1", got)
+	}
+}
@@ -0,0 +1,174 @@
+package logic
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	entropyMinLength = 32
+	entropyThreshold = 4.5
+)
+
+const RedactionMarker = "[REDACTED:"
+
+type secretDetector struct {
+	name     string
+	pattern  *regexp.Regexp
+	group    int
+	validate func(string) bool
+}
+
+type RedactionFinding struct {
+	Path     string
+	Detector string
+	Count    int
+}
+
+type Redactor struct {
+	detectors []secretDetector
+}
+
+var builtinDetectors = []secretDetector{
+	{"private-key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), 0, nil},
+	{"aws-access-key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), 0, nil},
+	{"github-token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`), 0, nil},
+	{"anthropic-key", regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`), 0, nil},
+	{"openai-key", regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`), 0, nil},
+	{"slack-token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`), 0, nil},
+	{"google-api-key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), 0, nil},
+	{"stripe-key", regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}\b`), 0, nil},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), 0, nil},
+	{"url-credentials", regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`), 1, nil},
+	{"secret-assignment", regexp.MustCompile(secretName + `\s*[:=]\s*"([^"\s]{8,})"`), 1, isLiteralSecret},
+	{"secret-assignment", regexp.MustCompile(secretName + `\s*[:=]\s*'([^'\s]{8,})'`), 1, isLiteralSecret},
+	{"secret-assignment", regexp.MustCompile(secretName + `\s*[:=]\s*([A-Za-z0-9+/=_.-]{16,})`), 1, looksRandom},
+}
+
+const secretName = `(?i)(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key)[A-Za-z0-9_]*["']?`
+
+var entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/=_-]{32,}`)
+
+func NewRedactor(patterns []string) (*Redactor, error) {
+	detectors := append([]secretDetector{}, builtinDetectors...)
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+		detectors = append(detectors, secretDetector{fmt.Sprintf("custom-%d", i+1), re, 0, nil})
+	}
+	return &Redactor{detectors: detectors}, nil
+}
+
+func shannonEntropy(text string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range text {
+		counts[r]++
+	}
+
+	entropy := 0.0
+	length := float64(len(text))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func isLiteralSecret(value string) bool {
+	return !strings.HasPrefix(value, "${") && !strings.HasPrefix(value, "{{") && !strings.HasPrefix(value, "%") &&
+		!strings.HasPrefix(value, RedactionMarker)
+}
+
+func looksRandom(value string) bool {
+	return strings.ContainsAny(value, "0123456789") && strings.ToUpper(value) != value && shannonEntropy(value) >= 3.5
+}
+
+func looksLikeSecret(candidate string) bool {
+	if len(candidate) < entropyMinLength || strings.HasPrefix(candidate, RedactionMarker) {
+		return false
+	}
+	hasDigit := strings.ContainsAny(candidate, "0123456789")
+	hasUpper := strings.ToLower(candidate) != candidate
+	hasLower := strings.ToUpper(candidate) != candidate
+	return hasDigit && hasUpper && hasLower && shannonEntropy(candidate) >= entropyThreshold
+}
+
+func replaceGroup(detector secretDetector, content string, replacement string) (string, int) {
+	re, group := detector.pattern, detector.group
+	count := 0
+	matches := re.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) == 0 {
+		return content, 0
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[2*group], match[2*group+1]
+		if start < 0 || start < last {
+			continue
+		}
+		if detector.validate != nil && !detector.validate(content[start:end]) {
+			continue
+		}
+		out.WriteString(content[last:start])
+		out.WriteString(replacement)
+		last = end
+		count++
+	}
+	out.WriteString(content[last:])
+	return out.String(), count
+}
+
+func (r *Redactor) Redact(content string) (string, map[string]int) {
+	counts := make(map[string]int)
+
+	for _, detector := range r.detectors {
+		var count int
+		content, count = replaceGroup(detector, content, RedactionMarker+detector.name+"]")
+		if count > 0 {
+			counts[detector.name] += count
+		}
+	}
+
+	content = entropyCandidate.ReplaceAllStringFunc(content, func(candidate string) string {
+		if !looksLikeSecret(candidate) {
+			return candidate
+		}
+		counts["high-entropy"]++
+		return RedactionMarker + "high-entropy]"
+	})
+
+	return content, counts
+}
+
+func (r *Redactor) RedactMessages(messages []Message) ([]Message, []RedactionFinding) {
+	redacted := make([]Message, len(messages))
+	var findings []RedactionFinding
+
+	for i, message := range messages {
+		redacted[i] = message
+		if message.Type != MessageTypeFile && message.Type != MessageTypeOutput {
+			continue
+		}
+
+		content, counts := r.Redact(message.Content)
+		redacted[i].Content = content
+
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			findings = append(findings, RedactionFinding{Path: message.Path, Detector: name, Count: counts[name]})
+		}
+	}
+
+	return redacted, findings
+}
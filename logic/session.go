@@ -0,0 +1,235 @@
+package logic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const DefaultSession = "default"
+
+var sessionNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+func getActiveSessionFilePath() (string, error) {
+	dir, err := getYactDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "session"), nil
+}
+
+func getSessionFilePath(name string) (string, error) {
+	dir, err := getYactDir()
+	if err != nil {
+		return "", err
+	}
+	if name == DefaultSession {
+		return filepath.Join(dir, "context.json"), nil
+	}
+	return filepath.Join(dir, "sessions", name+".json"), nil
+}
+
+func validateSessionName(name string) error {
+	if !sessionNamePattern.MatchString(name) || name == "." || name == ".." || strings.HasSuffix(name, ".branches") {
+		return fmt.Errorf("invalid session name '%s' (use letters, digits, '.', '_' and '-', not ending in '.branches')", name)
+	}
+	return nil
+}
+
+func sessionExists(name string) (bool, error) {
+	sessionPath, err := getSessionFilePath(name)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(sessionPath); err != nil {
+		if os.IsNotExist(err) {
+			return name == DefaultSession, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func ActiveSession() (string, error) {
+	activePath, err := getActiveSessionFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(activePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultSession, nil
+		}
+		return "", err
+	}
+
+	name := strings.TrimSpace(string(data))
+	if validateSessionName(name) != nil {
+		return DefaultSession, nil
+	}
+	return name, nil
+}
+
+func SetActiveSession(name string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+
+	exists, err := sessionExists(name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("session '%s' does not exist", name)
+	}
+
+	activePath, err := getActiveSessionFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(activePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(activePath, []byte(name+"\n"), 0644)
+}
+
+func ListSessions() ([]string, error) {
+	dir, err := getYactDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []string{DefaultSession}
+	entries, err := os.ReadDir(filepath.Join(dir, "sessions"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() || validateSessionName(name) != nil || name == DefaultSession {
+			continue
+		}
+		sessions = append(sessions, name)
+	}
+
+	sort.Strings(sessions[1:])
+	return sessions, nil
+}
+
+func CreateSession(name string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+
+	exists, err := sessionExists(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("session '%s' already exists", name)
+	}
+
+	sessionPath, err := getSessionFilePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(sessionPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(sessionPath, []byte("[]"), 0644)
+}
+
+func RemoveSession(name string) error {
+	if name == DefaultSession {
+		return fmt.Errorf("the default session cannot be removed")
+	}
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+
+	sessionPath, err := getSessionFilePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(sessionPath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("session '%s' does not exist", name)
+		}
+		return err
+	}
+	treePath, err := getTreeFilePathFor(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(treePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	active, err := ActiveSession()
+	if err != nil {
+		return err
+	}
+	if active == name {
+		return SetActiveSession(DefaultSession)
+	}
+	return nil
+}
+
+func RenameSession(oldName string, newName string) error {
+	if oldName == DefaultSession || newName == DefaultSession {
+		return fmt.Errorf("the default session cannot be renamed")
+	}
+	if err := validateSessionName(oldName); err != nil {
+		return err
+	}
+	if err := validateSessionName(newName); err != nil {
+		return err
+	}
+
+	exists, err := sessionExists(newName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("session '%s' already exists", newName)
+	}
+
+	oldPath, err := getSessionFilePath(oldName)
+	if err != nil {
+		return err
+	}
+	newPath, err := getSessionFilePath(newName)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("session '%s' does not exist", oldName)
+		}
+		return err
+	}
+	oldTreePath, err := getTreeFilePathFor(oldName)
+	if err != nil {
+		return err
+	}
+	newTreePath, err := getTreeFilePathFor(newName)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(oldTreePath, newTreePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	active, err := ActiveSession()
+	if err != nil {
+		return err
+	}
+	if active == oldName {
+		return SetActiveSession(newName)
+	}
+	return nil
+}
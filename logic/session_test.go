@@ -0,0 +1,65 @@
+package logic
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSessionsWithBranches(t *testing.T) {
+	root := setupYactDir(t)
+
+	if err := CreateSession("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetActiveSession("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateCheckpoint("cp1"); err != nil {
+		t.Fatalf("CreateCheckpoint: %v", err)
+	}
+
+	sessions, err := ListSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{DefaultSession, "foo"}; !reflect.DeepEqual(sessions, want) {
+		t.Errorf("ListSessions() = %q, want %q", sessions, want)
+	}
+
+	if err := RenameSession("foo", "bar"); err != nil {
+		t.Fatalf("RenameSession: %v", err)
+	}
+	tree, err := LoadConversationTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Find("cp1") == nil {
+		t.Error("checkpoint was lost when renaming the session")
+	}
+
+	if err := RemoveSession("bar"); err != nil {
+		t.Fatalf("RemoveSession: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".yact", "branches", "bar.json")); !os.IsNotExist(err) {
+		t.Errorf("branch tree of a removed session still exists: %v", err)
+	}
+}
+
+func TestListSessionsSkipsLegacyTreeFiles(t *testing.T) {
+	root := setupYactDir(t)
+	writeTestFile(t, filepath.Join(root, ".yact", "sessions", "foo.json"), "[]")
+	writeTestFile(t, filepath.Join(root, ".yact", "sessions", "foo.branches.json"), "{}")
+
+	sessions, err := ListSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{DefaultSession, "foo"}; !reflect.DeepEqual(sessions, want) {
+		t.Errorf("ListSessions() = %q, want %q", sessions, want)
+	}
+	if err := SetActiveSession("foo.branches"); err == nil {
+		t.Error("switching to a branch tree file succeeded")
+	}
+}
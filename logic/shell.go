@@ -0,0 +1,87 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+type CommandResult struct {
+	Command  string
+	Output   string
+	ExitCode int
+}
+
+func (r CommandResult) Succeeded() bool {
+	return r.ExitCode == 0
+}
+
+func RunCommand(ctx context.Context, dir string, echo io.Writer, name string, args ...string) (CommandResult, error) {
+	result := CommandResult{Command: strings.Join(append([]string{name}, args...), " ")}
+
+	var output bytes.Buffer
+	var writer io.Writer = &output
+	if echo != nil {
+		writer = io.MultiWriter(&output, echo)
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	err := cmd.Run()
+	result.Output = output.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		return result, fmt.Errorf("command cancelled: %s", result.Command)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return result, fmt.Errorf("error running %s: %w", result.Command, err)
+	}
+	return result, nil
+}
+
+func RunShell(ctx context.Context, dir string, command string) (CommandResult, error) {
+	var result CommandResult
+	var err error
+	if runtime.GOOS == "windows" {
+		result, err = RunCommand(ctx, dir, nil, "cmd", "/C", command)
+	} else {
+		result, err = RunCommand(ctx, dir, nil, "sh", "-c", command)
+	}
+	result.Command = command
+	return result, err
+}
+
+func TruncateOutput(output string, maxBytes int) string {
+	if len(output) <= maxBytes {
+		return output
+	}
+
+	half := maxBytes / 2
+	head := output[:half]
+	tail := output[len(output)-half:]
+	if idx := strings.LastIndex(head, "\n"); idx > 0 {
+		head = head[:idx+1]
+	}
+	if idx := strings.Index(tail, "\n"); idx >= 0 && idx < len(tail)-1 {
+		tail = tail[idx+1:]
+	}
+
+	omitted := len(output) - len(head) - len(tail)
+	return fmt.Sprintf("%s... [%d bytes truncated] ...\n%s", head, omitted, tail)
+}
+
+func (r CommandResult) AsMessageContent(maxBytes int) string {
+	return fmt.Sprintf("$ %s\nExit code: %d\n````\n%s\n````\n", r.Command, r.ExitCode, strings.TrimRight(TruncateOutput(r.Output, maxBytes), "\n"))
+}
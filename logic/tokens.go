@@ -0,0 +1,17 @@
+package logic
+
+import "unicode/utf8"
+
+const charsPerToken = 4
+
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+func EstimateMessagesTokens(messages []Message, systemPrompt string) int {
+	total := EstimateTokens(systemPrompt)
+	for _, message := range messages {
+		total += EstimateTokens(message.Content)
+	}
+	return total
+}
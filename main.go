@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"syscall"
 	"yact/config/systemprompt"
 	"yact/logic"
 
@@ -26,9 +29,37 @@ func getPromptFromStdin() (string, error) {
 	return string(data), nil
 }
 
+func handleInterrupts(cancel context.CancelFunc) {
+	interrupts := make(chan os.Signal, 2)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-interrupts
+		fmt.Fprintf(os.Stderr, "\rInterrupted, cancelling request (press Ctrl-C again to force exit)\n")
+		cancel()
+
+		<-interrupts
+		fmt.Fprintf(os.Stderr, "\rForced exit\n")
+		os.Exit(130)
+	}()
+}
+
 func main() {
 	helpFlag := flag.BoolP("help", "h", false, "Show help message")
 	safeFlag := flag.BoolP("safe", "s", false, "Add .new suffix to generated files")
+	dryRunFlag := flag.Bool("dry-run", false, "Show diffs of generated files without writing them")
+	flag.BoolVar(dryRunFlag, "diff", false, "Alias for --dry-run")
+	interactiveFlag := flag.BoolP("interactive", "i", false, "Review each generated file before writing it")
+	formatFlag := flag.String("format", "md", "Export format: md or json")
+	noRedactFlag := flag.Bool("no-redact", false, "With export, keep secrets in read files unmasked")
+	editFormatFlag := flag.String("edit-format", "", "Edit format for act, step and go: whole or search-replace")
+	missingFlag := flag.Bool("missing", false, "Remove files that no longer exist from context")
+	editFlag := flag.Bool("edit", false, "Edit the plan in $EDITOR before accepting it")
+	allFlag := flag.Bool("all", false, "Execute the remaining plan steps one by one")
+	fixFlag := flag.Int("fix", 0, "Repair rounds when verify commands fail after writing")
+	gitDiffFlag := flag.Bool("git-diff", false, "With read, add the diff against a base ref (default: HEAD)")
+	stagedFlag := flag.Bool("staged", false, "With read, use staged changes")
+	changedFlag := flag.Bool("changed", false, "With read, add every file changed against a base ref")
 
 	flag.Parse()
 
@@ -61,6 +92,29 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *editFormatFlag != "" {
+		if *editFormatFlag != config.EditFormatWhole && *editFormatFlag != config.EditFormatSearch {
+			fmt.Fprintf(os.Stderr, "Error: unknown edit format '%s'\n", *editFormatFlag)
+			os.Exit(1)
+		}
+		cfg.EditFormat = *editFormatFlag
+	}
+	if *fixFlag < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --fix must not be negative\n")
+		os.Exit(1)
+	}
+	actPrompt := commands.ActPrompt(cfg.EditFormat)
+	policy, err := logic.NewPathPolicy(cfg.AllowPaths, cfg.DenyPaths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	writeOptions := commands.WriteOptions{Safe: *safeFlag, DryRun: *dryRunFlag, Interactive: *interactiveFlag, Fix: *fixFlag, Policy: policy}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handleInterrupts(cancel)
+
 	command := args[0]
 	commandArgs := []string{}
 	if len(args) > 1 {
@@ -74,7 +128,12 @@ func main() {
 		commands.ShowHelp()
 		return
 	case "read":
-		commandErr = commands.HandleReadCommand(commandArgs)
+		gitOptions := commands.GitReadOptions{Diff: *gitDiffFlag, Staged: *stagedFlag, Changed: *changedFlag}
+		commandErr = commands.HandleReadCommand(ctx, commandArgs, gitOptions)
+	case "run":
+		commandErr = commands.HandleRunCommand(ctx, commandArgs)
+	case "unread":
+		commandErr = commands.HandleUnreadCommand(commandArgs, *missingFlag)
 	case "config":
 		commandErr = commands.HandleConfigCommand(commandArgs, cfg)
 	case "context":
@@ -96,13 +155,37 @@ func main() {
 		}
 		commandErr = commands.HandleResetCommand()
 	case "act":
-		commandErr = commands.HandleActCommand(commandArgs, *safeFlag, cfg, systemprompt.Act)
+		commandErr = commands.HandleActCommand(ctx, commandArgs, writeOptions, cfg, actPrompt)
 	case "bash":
-		commandErr = commands.HandleActCommand(commandArgs, *safeFlag, cfg, systemprompt.Bash)
+		commandErr = commands.HandleActCommand(ctx, commandArgs, writeOptions, cfg, systemprompt.Bash)
 	case "ask":
-		commandErr = commands.HandleVerbalCommand(commandArgs, cfg, systemprompt.Ask, logic.MessageTypeQuestion)
+		commandErr = commands.HandleVerbalCommand(ctx, commandArgs, cfg, systemprompt.Ask, logic.MessageTypeQuestion)
 	case "plan":
-		commandErr = commands.HandleVerbalCommand(commandArgs, cfg, systemprompt.Plan, logic.MessageTypeObjective)
+		commandErr = commands.HandleVerbalCommand(ctx, commandArgs, cfg, systemprompt.Plan, logic.MessageTypeObjective)
+	case "init":
+		if len(commandArgs) != 0 {
+			fmt.Fprintf(os.Stderr, "Error: the init command takes no arguments\n")
+			os.Exit(1)
+		}
+		commandErr = commands.HandleInitCommand()
+	case "session":
+		commandErr = commands.HandleSessionCommand(commandArgs)
+	case "compact":
+		if len(commandArgs) > 1 {
+			fmt.Fprintf(os.Stderr, "Error: compact command takes at most one argument\n")
+			os.Exit(1)
+		}
+		commandErr = commands.HandleCompactCommand(ctx, commandArgs, cfg)
+	case "export":
+		commandErr = commands.HandleExportCommand(commandArgs, *formatFlag, !*noRedactFlag, cfg)
+	case "import":
+		commandErr = commands.HandleImportCommand(commandArgs)
+	case "checkpoint":
+		commandErr = commands.HandleCheckpointCommand(commandArgs)
+	case "branch":
+		commandErr = commands.HandleBranchCommand(commandArgs)
+	case "checkout":
+		commandErr = commands.HandleCheckoutCommand(commandArgs)
 	case "new":
 		commandErr = commands.HandleNewCommand()
 	case "last":
@@ -120,15 +203,47 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: step index required\n")
 			os.Exit(1)
 		}
-		stepArgs := append([]string{"implement", "step"}, commandArgs...)
-		stepArgs = append(stepArgs, ". Make no other changes.")
-		commandErr = commands.HandleActCommand(stepArgs, *safeFlag, cfg, systemprompt.Act)
+		commandErr = commands.HandleStepCommand(ctx, commandArgs, writeOptions, cfg, actPrompt)
+	case "steps":
+		if len(commandArgs) != 0 {
+			fmt.Fprintf(os.Stderr, "Error: the steps command takes no arguments\n")
+			os.Exit(1)
+		}
+		commandErr = commands.HandleStepsCommand()
 	case "go":
 		if len(commandArgs) != 0 {
 			fmt.Fprintf(os.Stderr, "Error: the go command takes no arguments\n")
 			os.Exit(1)
 		}
-		commandErr = commands.HandleGoCommand(cfg, systemprompt.Act)
+		if *allFlag {
+			commandErr = commands.HandleGoAllCommand(ctx, writeOptions, cfg, actPrompt)
+		} else {
+			commandErr = commands.HandleGoCommand(ctx, writeOptions, cfg, actPrompt)
+		}
+	case "accept":
+		if len(commandArgs) != 0 {
+			fmt.Fprintf(os.Stderr, "Error: the accept command takes no arguments\n")
+			os.Exit(1)
+		}
+		commandErr = commands.HandleAcceptCommand(*editFlag)
+	case "undo":
+		if len(commandArgs) > 1 {
+			fmt.Fprintf(os.Stderr, "Error: undo command takes at most one argument\n")
+			os.Exit(1)
+		}
+		commandErr = commands.HandleUndoCommand(commandArgs)
+	case "redo":
+		if len(commandArgs) > 1 {
+			fmt.Fprintf(os.Stderr, "Error: redo command takes at most one argument\n")
+			os.Exit(1)
+		}
+		commandErr = commands.HandleRedoCommand(commandArgs)
+	case "retry":
+		if len(commandArgs) != 0 {
+			fmt.Fprintf(os.Stderr, "Error: the retry command takes no arguments\n")
+			os.Exit(1)
+		}
+		commandErr = commands.HandleRetryCommand(ctx, writeOptions, cfg)
 	default:
 		fmt.Printf("Error: Unknown command '%s'\n", command)
 		fmt.Println("Run 'y --help' for usage information.")